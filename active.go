@@ -0,0 +1,43 @@
+package retry
+
+import (
+	"context"
+	"time"
+)
+
+// activePollInterval is how often an idle retrier checks whether it became
+// active again.
+const activePollInterval = 100 * time.Millisecond
+
+// WithActiveFunc makes attempts run only while active returns true, e.g.
+// while the instance is the leader of an HA component, so that replicas do
+// not all retry the same work. While it returns false, Do idles before the
+// next attempt, polling it until it returns true or ctx is done.
+func WithActiveFunc(active func() bool) RetryOption {
+	return func(r *retrier) {
+		r.active = active
+	}
+}
+
+// awaitActive waits until the retrier is active, or until ctx is done or
+// stop is closed.
+func (r retrier) awaitActive(ctx context.Context, stop <-chan struct{}) error {
+	if r.active == nil || r.active() {
+		return nil
+	}
+
+	ticker := time.NewTicker(activePollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-stop:
+			return ErrShutdown
+		case <-ticker.C:
+			if r.active() {
+				return nil
+			}
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package retry
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithActiveFunc(t *testing.T) {
+	var leader atomic.Bool
+	r := New(WithActiveFunc(leader.Load))
+
+	var ran atomic.Bool
+	done := make(chan error)
+	go func() {
+		done <- r.Do(context.Background(), func(int) error {
+			ran.Store(true)
+			return nil
+		})
+	}()
+
+	time.Sleep(2 * activePollInterval)
+	assert.False(t, ran.Load(), "followers idle")
+	leader.Store(true)
+	assert.NoError(t, <-done)
+	assert.True(t, ran.Load())
+}
+
+func TestWithActiveFunc_ContextDone(t *testing.T) {
+	r := New(WithActiveFunc(func() bool { return false }))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := r.Do(ctx, func(int) error {
+		t.Fatal("attempt ran while inactive")
+		return nil
+	})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
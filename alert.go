@@ -0,0 +1,60 @@
+package retry
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// WithExhaustionAlert calls fn once n consecutive Do calls have run out of
+// attempts, with the context and error of the n-th call, to drive paging
+// without alerting on a single flaky call. Any call that does not end in
+// exhaustion resets the streak, and fn is called again only after another
+// n consecutive exhaustions.
+func WithExhaustionAlert(n int, fn func(ctx context.Context, err error)) RetryOption {
+	return func(r *retrier) {
+		r.extraHooks = append(r.extraHooks, &alertHook{n: int64(max(n, 1)), fn: fn})
+	}
+}
+
+// alertHook counts consecutive exhaustions.
+type alertHook struct {
+	nopHook
+	n      int64
+	fn     func(context.Context, error)
+	streak atomic.Int64
+}
+
+func (h *alertHook) finished(ctx context.Context, _ int, err error) {
+	if !isExhausted(err) {
+		h.streak.Store(0)
+		return
+	}
+	if h.streak.Add(1) == h.n {
+		h.streak.Store(0)
+		h.fn(ctx, err)
+	}
+}
+
+// WithEscalateAfter calls fn with the error of the n-th failed attempt of a
+// Do call, so long-running calls such as reconnect loops with unlimited
+// attempts can raise the severity of persisting failures. fn is called at
+// most once per call.
+func WithEscalateAfter(n int, fn func(err error)) RetryOption {
+	return func(r *retrier) {
+		r.extraHooks = append(r.extraHooks, escalateHook{n: max(n, 1), fn: fn})
+	}
+}
+
+// escalateHook calls fn after the n-th failed attempt.
+type escalateHook struct {
+	nopHook
+	n  int
+	fn func(error)
+}
+
+func (h escalateHook) attemptFinished(_ context.Context, attempt int, err error, _ time.Duration) {
+	if err != nil && attempt+1 == h.n {
+		h.fn(err)
+	}
+}
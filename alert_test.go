@@ -0,0 +1,67 @@
+package retry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithExhaustionAlert(t *testing.T) {
+	var alerts []error
+	r := New(
+		WithMaxAttempts(1),
+		WithUnretryableErrors(errCustom),
+		WithExhaustionAlert(3, func(ctx context.Context, err error) {
+			alerts = append(alerts, err)
+		}),
+	)
+	call := func(err error) {
+		_ = r.Do(context.Background(), func(attempt int) error { return err })
+	}
+
+	call(errAlwaysFail)
+	call(errAlwaysFail)
+	call(nil)
+	call(errAlwaysFail)
+	call(errAlwaysFail)
+	call(errCustom)
+	assert.Empty(t, alerts, "successes and unretryable errors reset the streak")
+
+	call(errAlwaysFail)
+	call(errAlwaysFail)
+	call(errAlwaysFail)
+	assert.Len(t, alerts, 1)
+	assert.True(t, isExhausted(alerts[0]))
+
+	call(errAlwaysFail)
+	call(errAlwaysFail)
+	assert.Len(t, alerts, 1)
+	call(errAlwaysFail)
+	assert.Len(t, alerts, 2)
+}
+
+func TestWithEscalateAfter(t *testing.T) {
+	var escalated []error
+	r := New(
+		WithMaxAttempts(0),
+		WithBackoff(FixedBackoff{}),
+		WithEscalateAfter(3, func(err error) { escalated = append(escalated, err) }),
+	)
+
+	_ = r.Do(context.Background(), func(attempt int) error {
+		if attempt < 2 {
+			return errAlwaysFail
+		}
+		return nil
+	})
+	assert.Empty(t, escalated)
+
+	_ = r.Do(context.Background(), func(attempt int) error {
+		if attempt < 6 {
+			return errCustom
+		}
+		return nil
+	})
+	assert.Equal(t, []error{errCustom}, escalated)
+}
@@ -0,0 +1,73 @@
+package retry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+// WithAuditWriter writes a JSON record, one per line, to w for every Do
+// call that runs out of attempts. The record carries the retrier name, the
+// correlation ID, the attempt count, the elapsed time and the message of
+// every attempt error collected with WithErrorAggregation, or of the last
+// one otherwise.
+// It does not depend on the metrics stack, for compliance pipelines.
+// Writes are serialized; write errors are ignored.
+func WithAuditWriter(w io.Writer) RetryOption {
+	return func(r *retrier) {
+		r.extraHooks = append(r.extraHooks, &auditHook{w: w, r: r})
+	}
+}
+
+// auditRecord is the record written by WithAuditWriter.
+type auditRecord struct {
+	Time      time.Time `json:"time"`
+	Retrier   string    `json:"retrier,omitempty"`
+	CallID    string    `json:"call_id,omitempty"`
+	Code      string    `json:"code"`
+	Attempts  int       `json:"attempts"`
+	ElapsedMS int64     `json:"elapsed_ms"`
+	Errors    []string  `json:"errors"`
+}
+
+// auditHook writes audit records for exhausted calls.
+type auditHook struct {
+	nopHook
+	mu sync.Mutex
+	w  io.Writer
+	r  *retrier
+}
+
+func (h *auditHook) finished(ctx context.Context, _ int, err error) {
+	var e *MaxAttemptsError
+	if !errors.As(err, &e) {
+		return
+	}
+
+	rec := auditRecord{
+		Time:      time.Now().UTC(),
+		Retrier:   h.r.name,
+		Code:      e.Code(),
+		Attempts:  e.Attempts,
+		ElapsedMS: e.Elapsed.Milliseconds(),
+	}
+	rec.CallID, _ = CorrelationID(ctx)
+	if agg, ok := e.err.(*aggregateError); ok {
+		for _, err := range agg.errs {
+			rec.Errors = append(rec.Errors, err.Error())
+		}
+	} else if e.err != nil {
+		rec.Errors = []string{e.err.Error()}
+	}
+
+	data, jerr := json.Marshal(rec)
+	if jerr != nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, _ = h.w.Write(append(data, '\n'))
+}
@@ -0,0 +1,60 @@
+package retry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithAuditWriter(t *testing.T) {
+	var buf bytes.Buffer
+	r := New(
+		WithName("ledger"),
+		WithMaxAttempts(3),
+		WithBackoff(FixedBackoff{}),
+		WithErrorAggregation(),
+		WithUnretryableErrors(errCustom),
+		WithAuditWriter(&buf),
+	)
+
+	_ = r.Do(context.Background(), func(attempt int) error { return nil })
+	_ = r.Do(context.Background(), func(attempt int) error { return errCustom })
+	assert.Empty(t, buf.String(), "only exhausted calls are audited")
+
+	_ = r.Do(context.Background(), func(attempt int) error {
+		if attempt == 2 {
+			return errCustom
+		}
+		return errAlwaysFail
+	})
+	assert.Empty(t, buf.String())
+
+	_ = r.Do(context.Background(), func(attempt int) error { return errAlwaysFail })
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 1)
+
+	var rec map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &rec))
+	assert.Equal(t, "ledger", rec["retrier"])
+	assert.Equal(t, CodeExhausted, rec["code"])
+	assert.Equal(t, float64(3), rec["attempts"])
+	assert.Equal(t, []any{"always fail", "always fail", "always fail"}, rec["errors"])
+	assert.Contains(t, rec, "time")
+	assert.Contains(t, rec, "elapsed_ms")
+}
+
+func TestWithAuditWriter_LastError(t *testing.T) {
+	var buf bytes.Buffer
+	r := New(WithMaxAttempts(2), WithBackoff(FixedBackoff{}), WithAuditWriter(&buf))
+	_ = r.Do(context.Background(), func(attempt int) error { return errAlwaysFail })
+
+	var rec map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &rec))
+	assert.Equal(t, []any{"always fail"}, rec["errors"])
+	assert.NotContains(t, rec, "retrier")
+}
@@ -83,3 +83,46 @@ func addJitter(d time.Duration, jitter float64) time.Duration {
 	delta := (rand.Float64()*2 - 1) * jitter
 	return time.Duration(float64(d) * (1 + delta))
 }
+
+// IntervalWindowBackoff grows an interval exponentially and draws each delay
+// uniformly from a window around it.
+//
+// Interval is the initial interval.
+// Multiplier grows the interval for each subsequent attempt (values below 1 are treated as 1).
+// Max caps the interval before randomization (0 means no limit).
+// RandomizationFactor defines the window as a fraction of the interval:
+// the delay is drawn from [interval*(1-rf), interval*(1+rf)].
+//
+// Unlike Jitter on the other strategies, the window is applied after the
+// cap, so delays stay spread out even once Max is reached.
+type IntervalWindowBackoff struct {
+	Interval            time.Duration
+	Multiplier          float64
+	Max                 time.Duration
+	RandomizationFactor float64
+}
+
+// Next returns a delay drawn uniformly from the randomization window
+// around the capped interval for the given attempt.
+func (w IntervalWindowBackoff) Next(attempt int) time.Duration {
+	m := w.Multiplier
+	if m < 1 {
+		m = 1
+	}
+	d := float64(w.Interval) * math.Pow(m, float64(attempt))
+	if w.Max > 0 && d > float64(w.Max) {
+		d = float64(w.Max)
+	}
+	return randomizeWindow(time.Duration(d), w.RandomizationFactor)
+}
+
+// randomizeWindow draws a duration uniformly from [d*(1-rf), d*(1+rf)].
+// Factors outside the range (0, 1] disable randomization.
+func randomizeWindow(d time.Duration, rf float64) time.Duration {
+	if rf <= 0 || rf > 1 {
+		return d
+	}
+	low := float64(d) * (1 - rf)
+	high := float64(d) * (1 + rf)
+	return time.Duration(low + rand.Float64()*(high-low))
+}
@@ -108,3 +108,58 @@ func TestAddJitter(t *testing.T) {
 		}
 	})
 }
+
+func TestIntervalWindowBackoff(t *testing.T) {
+	t.Run("no randomization", func(t *testing.T) {
+		b := IntervalWindowBackoff{Interval: time.Second, Multiplier: 2, Max: 5 * time.Second}
+		tests := []struct {
+			attempt int
+			want    time.Duration
+		}{
+			{0, 1 * time.Second},
+			{1, 2 * time.Second},
+			{2, 4 * time.Second},
+			{3, 5 * time.Second}, // capped by Max
+		}
+
+		for _, tt := range tests {
+			got := b.Next(tt.attempt)
+			if got != tt.want {
+				t.Errorf("attempt %d: expected %v, got %v", tt.attempt, tt.want, got)
+			}
+		}
+	})
+
+	t.Run("multiplier below one", func(t *testing.T) {
+		b := IntervalWindowBackoff{Interval: time.Second, Multiplier: 0.5}
+		got := b.Next(4)
+		if got != time.Second {
+			t.Errorf("expected 1s, got %v", got)
+		}
+	})
+
+	t.Run("window applied after cap", func(t *testing.T) {
+		b := IntervalWindowBackoff{Interval: time.Second, Multiplier: 2, Max: 2 * time.Second, RandomizationFactor: 0.5}
+		for i := 0; i < 100; i++ {
+			inRange(t, b.Next(10), 2*time.Second, 0.5)
+		}
+	})
+}
+
+func TestRandomizeWindow(t *testing.T) {
+	t.Run("full window", func(t *testing.T) {
+		for i := 0; i < 100; i++ {
+			got := randomizeWindow(time.Second, 1)
+			if got < 0 || got > 2*time.Second {
+				t.Errorf("expected within [0s, 2s], got %v", got)
+			}
+		}
+	})
+
+	t.Run("invalid factor >1", func(t *testing.T) {
+		got := randomizeWindow(time.Second, 1.5)
+		if got != time.Second {
+			t.Errorf("expected 1s, got %v", got)
+		}
+	})
+}
@@ -0,0 +1,37 @@
+package retry
+
+import "math/rand/v2"
+
+// maxPressureScale is the factor backoff delays are multiplied by at full
+// downstream pressure.
+const maxPressureScale = 10
+
+// WithBackpressure makes retries ease off when the dependency reports
+// overload. pressure returns the current downstream pressure, from 0 (none)
+// to 1 (overloaded), and is called before every retry. Each retry is
+// skipped with a probability equal to the pressure, in which case the call
+// returns a BudgetExceededError, and its backoff delay is scaled up
+// linearly to ten times the regular one at full pressure.
+func WithBackpressure(pressure func() float64) RetryOption {
+	return func(r *retrier) {
+		r.backpressure = pressure
+	}
+}
+
+// pressure returns the current downstream pressure, clamped to [0, 1].
+func (r retrier) pressure() float64 {
+	if r.backpressure == nil {
+		return 0
+	}
+	return min(max(r.backpressure(), 0), 1)
+}
+
+// shedByPressure reports whether a retry is skipped under pressure p.
+func shedByPressure(p float64) bool {
+	return p > 0 && rand.Float64() < p
+}
+
+// pressureScale returns the backoff delay multiplier under pressure p.
+func pressureScale(p float64) float64 {
+	return 1 + (maxPressureScale-1)*p
+}
@@ -0,0 +1,56 @@
+package retry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithBackpressure(t *testing.T) {
+	tests := []struct {
+		name      string
+		pressure  float64
+		wantCalls int
+		wantDelay time.Duration
+	}{
+		{name: "none", pressure: 0, wantCalls: 2, wantDelay: time.Millisecond},
+		{name: "negative is none", pressure: -1, wantCalls: 2, wantDelay: time.Millisecond},
+		{name: "full", pressure: 1, wantCalls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var delays []time.Duration
+			r := New(
+				WithMaxAttempts(2),
+				WithBackoff(FixedBackoff{Interval: time.Millisecond}),
+				WithBackpressure(func() float64 { return tt.pressure }),
+				WithOnRetry(func(_ context.Context, _ int, _ error, d time.Duration) {
+					delays = append(delays, d)
+				}),
+			)
+
+			calls := 0
+			err := r.Do(context.Background(), func(int) error {
+				calls++
+				return errAlwaysFail
+			})
+			assert.Error(t, err)
+			assert.Equal(t, tt.wantCalls, calls)
+			if tt.wantCalls == 1 {
+				assert.ErrorIs(t, err, ErrBudgetExceeded)
+				assert.Empty(t, delays)
+			} else {
+				assert.Equal(t, []time.Duration{tt.wantDelay}, delays)
+			}
+		})
+	}
+}
+
+func TestPressureScale(t *testing.T) {
+	assert.Equal(t, 1.0, pressureScale(0))
+	assert.Equal(t, 5.5, pressureScale(0.5))
+	assert.Equal(t, 10.0, pressureScale(1))
+}
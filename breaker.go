@@ -0,0 +1,284 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned by attempts rejected by an open circuit
+// breaker. It is never retried.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitState is the state of a CircuitBreaker.
+type CircuitState int
+
+const (
+	// CircuitClosed lets every attempt through.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen rejects every attempt with ErrCircuitOpen.
+	CircuitOpen
+	// CircuitHalfOpen lets a limited number of probe attempts through to
+	// decide whether to close the circuit again.
+	CircuitHalfOpen
+)
+
+// String returns the name of the state.
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return "CircuitState(unknown)"
+}
+
+// BreakerOption configures a CircuitBreaker.
+type BreakerOption func(*CircuitBreaker)
+
+// WithConsecutiveFailures opens the circuit after n consecutive failed
+// attempts. A value of 0 disables the threshold. The default is 5.
+func WithConsecutiveFailures(n int) BreakerOption {
+	return func(cb *CircuitBreaker) {
+		cb.maxConsecutive = n
+	}
+}
+
+// WithFailureRate opens the circuit when at least rate of the last window
+// attempts failed, once window attempts have been recorded. It is disabled
+// by default, and a rate of 0 or less disables it.
+func WithFailureRate(rate float64, window int) BreakerOption {
+	return func(cb *CircuitBreaker) {
+		if rate <= 0 {
+			cb.failureRate, cb.outcomes = 0, nil
+			return
+		}
+		cb.failureRate = rate
+		cb.outcomes = make([]bool, max(window, 0))
+	}
+}
+
+// WithOpenTimeout sets how long the circuit stays open before letting
+// probes through. The default is 30 seconds.
+func WithOpenTimeout(d time.Duration) BreakerOption {
+	return func(cb *CircuitBreaker) {
+		cb.openTimeout = d
+	}
+}
+
+// WithHalfOpenProbes sets how many probe attempts the half-open circuit
+// lets through; they must all succeed to close it, and any failure opens
+// it again. The default is 1.
+func WithHalfOpenProbes(n int) BreakerOption {
+	return func(cb *CircuitBreaker) {
+		cb.probes = max(n, 1)
+	}
+}
+
+// CircuitBreaker stops calls to a failing dependency: after too many
+// failures it opens and rejects attempts until a timeout elapses, then lets
+// probes through to decide whether the dependency recovered. It is safe for
+// concurrent use and can be shared by several retriers.
+type CircuitBreaker struct {
+	maxConsecutive int
+	failureRate    float64
+	openTimeout    time.Duration
+	probes         int
+	now            func() time.Time
+
+	mu          sync.Mutex
+	state       CircuitState
+	gen         uint64 // incremented on every change of state
+	consecutive int
+	outcomes    []bool // ring of recent outcomes, true for failures
+	next        int
+	recorded    int
+	openedAt    time.Time
+	inProbe     int
+	probeOK     int
+}
+
+// NewCircuitBreaker creates a closed CircuitBreaker.
+func NewCircuitBreaker(opts ...BreakerOption) *CircuitBreaker {
+	cb := &CircuitBreaker{
+		maxConsecutive: 5,
+		openTimeout:    30 * time.Second,
+		probes:         1,
+		now:            time.Now,
+	}
+	for _, opt := range opts {
+		opt(cb)
+	}
+	return cb
+}
+
+// State returns the current state of the circuit.
+func (cb *CircuitBreaker) State() CircuitState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if cb.state == CircuitOpen && cb.now().Sub(cb.openedAt) >= cb.openTimeout {
+		return CircuitHalfOpen
+	}
+	return cb.state
+}
+
+// allow reports whether an attempt may proceed, and the generation of the
+// state that admitted it, to pass to record or cancelProbe.
+func (cb *CircuitBreaker) allow() (gen uint64, ok bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.state {
+	case CircuitOpen:
+		if cb.now().Sub(cb.openedAt) < cb.openTimeout {
+			return cb.gen, false
+		}
+		cb.state = CircuitHalfOpen
+		cb.gen++
+		cb.inProbe, cb.probeOK = 0, 0
+		fallthrough
+	case CircuitHalfOpen:
+		if cb.inProbe >= cb.probes {
+			return cb.gen, false
+		}
+		cb.inProbe++
+	}
+	return cb.gen, true
+}
+
+// record records the outcome of an attempt admitted by generation gen.
+// Outcomes of attempts admitted before the last change of state are
+// ignored: a slow attempt of the closed circuit must not close it again, nor
+// a stale probe judge the next half-open state.
+func (cb *CircuitBreaker) record(gen uint64, failed bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if gen != cb.gen {
+		return
+	}
+	if cb.state == CircuitHalfOpen {
+		if failed {
+			cb.open()
+			return
+		}
+		cb.probeOK++
+		if cb.probeOK >= cb.probes {
+			cb.reset()
+		}
+		return
+	}
+	if cb.state == CircuitOpen {
+		return
+	}
+
+	if failed {
+		cb.consecutive++
+	} else {
+		cb.consecutive = 0
+	}
+	if len(cb.outcomes) > 0 {
+		cb.outcomes[cb.next] = failed
+		cb.next = (cb.next + 1) % len(cb.outcomes)
+		cb.recorded = min(cb.recorded+1, len(cb.outcomes))
+	}
+
+	if (cb.maxConsecutive > 0 && cb.consecutive >= cb.maxConsecutive) || cb.rateExceeded() {
+		cb.open()
+	}
+}
+
+// rateExceeded reports whether the failure rate over a full window reached
+// the threshold.
+func (cb *CircuitBreaker) rateExceeded() bool {
+	if len(cb.outcomes) == 0 || cb.recorded < len(cb.outcomes) {
+		return false
+	}
+	var failures int
+	for _, failed := range cb.outcomes {
+		if failed {
+			failures++
+		}
+	}
+	return float64(failures) >= cb.failureRate*float64(len(cb.outcomes))
+}
+
+func (cb *CircuitBreaker) open() {
+	cb.state = CircuitOpen
+	cb.gen++
+	cb.openedAt = cb.now()
+}
+
+func (cb *CircuitBreaker) reset() {
+	cb.state = CircuitClosed
+	cb.gen++
+	cb.consecutive = 0
+	cb.recorded, cb.next = 0, 0
+	clear(cb.outcomes)
+}
+
+// WithCircuitBreaker guards every attempt with cb. While the circuit is
+// open, attempts fail fast with ErrCircuitOpen, which stops retries.
+// Attempts failing with an error the retrier would retry count as
+// failures; successes and other errors, which show the dependency is
+// responding, count as successes. Attempts that exceed their deadline count
+// as failures, while attempts whose context is canceled are not counted.
+func WithCircuitBreaker(cb *CircuitBreaker) RetryOption {
+	return func(r *retrier) {
+		r.breaker = cb
+		r.middleware = append(r.middleware, func(next ContextAttemptFunc) ContextAttemptFunc {
+			return func(ctx context.Context, attempt int) error {
+				gen, ok := cb.allow()
+				if !ok {
+					return ErrCircuitOpen
+				}
+
+				err := next(ctx, attempt)
+				if err != nil && errors.Is(ctx.Err(), context.Canceled) {
+					// Release a probe slot without judging the dependency.
+					cb.cancelProbe(gen)
+					return err
+				}
+				failed := err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded)
+				if err != nil && !failed {
+					failed, _ = r.classify(err)
+				}
+				cb.record(gen, failed)
+				return err
+			}
+		})
+	}
+}
+
+// Do runs f if the circuit lets it through, returning ErrCircuitOpen
+// otherwise, and records its outcome: any error other than the
+// cancellation of ctx counts as a failure. Use it to guard operations run
+// outside a retrier.
+func (cb *CircuitBreaker) Do(ctx context.Context, f func(context.Context) error) error {
+	gen, ok := cb.allow()
+	if !ok {
+		return ErrCircuitOpen
+	}
+
+	err := f(ctx)
+	if err != nil && errors.Is(ctx.Err(), context.Canceled) {
+		cb.cancelProbe(gen)
+		return err
+	}
+	cb.record(gen, err != nil)
+	return err
+}
+
+// cancelProbe releases the probe slot of an attempt admitted by generation
+// gen that was not counted.
+func (cb *CircuitBreaker) cancelProbe(gen uint64) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if gen == cb.gen && cb.state == CircuitHalfOpen && cb.inProbe > 0 {
+		cb.inProbe--
+	}
+}
@@ -0,0 +1,182 @@
+package retry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock is a manually advanced clock for circuit breaker tests.
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTestBreaker(opts ...BreakerOption) (*CircuitBreaker, *fakeClock) {
+	clock := &fakeClock{t: time.Unix(0, 0)}
+	cb := NewCircuitBreaker(opts...)
+	cb.now = clock.now
+	return cb, clock
+}
+
+// admit admits an attempt through cb, returning the generation to record
+// its outcome with.
+func admit(t *testing.T, cb *CircuitBreaker) uint64 {
+	t.Helper()
+	gen, ok := cb.allow()
+	require.True(t, ok)
+	return gen
+}
+
+// allowed reports whether cb admits an attempt.
+func allowed(cb *CircuitBreaker) bool {
+	_, ok := cb.allow()
+	return ok
+}
+
+func TestCircuitBreaker_ConsecutiveFailures(t *testing.T) {
+	cb, clock := newTestBreaker(WithConsecutiveFailures(3), WithOpenTimeout(time.Minute))
+
+	for range 2 {
+		cb.record(admit(t, cb), true)
+	}
+	cb.record(admit(t, cb), false)
+	assert.Equal(t, CircuitClosed, cb.State(), "a success resets the streak")
+
+	for range 3 {
+		cb.record(admit(t, cb), true)
+	}
+	assert.Equal(t, CircuitOpen, cb.State())
+	assert.False(t, allowed(cb))
+
+	clock.advance(time.Minute)
+	assert.Equal(t, CircuitHalfOpen, cb.State())
+	probe := admit(t, cb)
+	assert.False(t, allowed(cb), "only one probe at a time")
+	cb.record(probe, false)
+	assert.Equal(t, CircuitClosed, cb.State())
+}
+
+func TestCircuitBreaker_HalfOpenFailure(t *testing.T) {
+	cb, clock := newTestBreaker(WithConsecutiveFailures(1), WithOpenTimeout(time.Second), WithHalfOpenProbes(2))
+
+	cb.record(admit(t, cb), true)
+	clock.advance(time.Second)
+
+	first, second := admit(t, cb), admit(t, cb)
+	cb.record(first, false)
+	assert.Equal(t, CircuitHalfOpen, cb.State(), "every probe must succeed")
+	cb.record(second, true)
+	assert.Equal(t, CircuitOpen, cb.State())
+	assert.False(t, allowed(cb))
+}
+
+func TestCircuitBreaker_FailureRate(t *testing.T) {
+	cb, _ := newTestBreaker(WithConsecutiveFailures(0), WithFailureRate(0.5, 4))
+
+	for _, failed := range []bool{true, false, true} {
+		cb.record(admit(t, cb), failed)
+	}
+	assert.Equal(t, CircuitClosed, cb.State(), "the window is not full yet")
+
+	cb.record(admit(t, cb), false)
+	assert.Equal(t, CircuitOpen, cb.State())
+}
+
+func TestCircuitBreaker_FailureRateDisabled(t *testing.T) {
+	cb, _ := newTestBreaker(WithConsecutiveFailures(0), WithFailureRate(0, 2))
+
+	for range 4 {
+		cb.record(admit(t, cb), false)
+	}
+	assert.Equal(t, CircuitClosed, cb.State(), "a rate of 0 disables the threshold")
+}
+
+func TestCircuitBreaker_StaleOutcomes(t *testing.T) {
+	cb, clock := newTestBreaker(WithConsecutiveFailures(1), WithOpenTimeout(time.Minute))
+
+	slow := admit(t, cb)
+	cb.record(admit(t, cb), true)
+	clock.advance(time.Minute)
+	probe := admit(t, cb)
+
+	cb.record(slow, false)
+	assert.Equal(t, CircuitHalfOpen, cb.State(), "an attempt of the closed circuit does not judge the probe")
+
+	cb.record(probe, true)
+	clock.advance(time.Minute)
+	next := admit(t, cb)
+	cb.record(probe, false)
+	assert.Equal(t, CircuitHalfOpen, cb.State(), "a probe of an earlier half-open state is ignored")
+	cb.record(next, false)
+	assert.Equal(t, CircuitClosed, cb.State())
+}
+
+func TestWithCircuitBreaker(t *testing.T) {
+	cb, clock := newTestBreaker(WithConsecutiveFailures(3), WithOpenTimeout(time.Minute))
+	r := New(
+		WithMaxAttempts(5),
+		WithBackoff(FixedBackoff{}),
+		WithUnretryableErrors(errCustom),
+		WithCircuitBreaker(cb),
+	)
+
+	calls := 0
+	err := r.Do(context.Background(), func(attempt int) error {
+		calls++
+		return errAlwaysFail
+	})
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.True(t, IsUnretryable(err))
+	assert.Equal(t, 3, calls, "attempts stop once the circuit opens")
+
+	err = r.Do(context.Background(), func(attempt int) error {
+		calls++
+		return nil
+	})
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, 3, calls, "the open circuit fails fast")
+
+	clock.advance(time.Minute)
+	err = r.Do(context.Background(), func(attempt int) error {
+		calls++
+		return errCustom
+	})
+	assert.ErrorIs(t, err, errCustom)
+	assert.Equal(t, CircuitClosed, cb.State(), "unretryable errors show the dependency responds")
+}
+
+func TestWithCircuitBreaker_Canceled(t *testing.T) {
+	cb, clock := newTestBreaker(WithConsecutiveFailures(1), WithOpenTimeout(time.Minute))
+	cb.record(admit(t, cb), true)
+	clock.advance(time.Minute)
+
+	r := New(WithMaxAttempts(1), WithCircuitBreaker(cb))
+	ctx, cancel := context.WithCancel(context.Background())
+	_ = r.DoContext(ctx, func(ctx context.Context, attempt int) error {
+		cancel()
+		return ctx.Err()
+	})
+
+	assert.Equal(t, CircuitHalfOpen, cb.State())
+	assert.True(t, allowed(cb), "the probe slot is released")
+}
+
+func TestCircuitState_String(t *testing.T) {
+	assert.Equal(t, "closed", CircuitClosed.String())
+	assert.Equal(t, "open", CircuitOpen.String())
+	assert.Equal(t, "half-open", CircuitHalfOpen.String())
+	assert.Equal(t, "CircuitState(unknown)", CircuitState(9).String())
+}
+
+func TestCircuitBreaker_Do(t *testing.T) {
+	cb, _ := newTestBreaker(WithConsecutiveFailures(2))
+	fail := func(context.Context) error { return errAlwaysFail }
+
+	assert.ErrorIs(t, cb.Do(context.Background(), fail), errAlwaysFail)
+	assert.ErrorIs(t, cb.Do(context.Background(), fail), errAlwaysFail)
+	assert.ErrorIs(t, cb.Do(context.Background(), fail), ErrCircuitOpen)
+}
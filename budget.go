@@ -0,0 +1,310 @@
+package retry
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// budgetBuckets is the number of buckets a Budget window is split into.
+const budgetBuckets = 10
+
+// BudgetOption configures a Budget.
+type BudgetOption func(*Budget)
+
+// WithMinRetries allows n retries per window regardless of the ratio, so
+// low-traffic callers can still retry. The default is 10.
+func WithMinRetries(n int) BudgetOption {
+	return func(b *Budget) {
+		b.minRetries = float64(max(n, 0))
+	}
+}
+
+// Budget caps retries as a ratio of calls over a sliding window, e.g. at
+// most 20% of calls over 10 seconds, so retries cannot multiply the load on
+// a struggling dependency. It is safe for concurrent use and is meant to be
+// shared by the retriers calling the same dependency, see WithBudget.
+type Budget struct {
+	ratio      float64
+	minRetries float64
+	bucketSize time.Duration
+	now        func() time.Time
+
+	mu      sync.Mutex
+	buckets [budgetBuckets]budgetBucket
+}
+
+// budgetBucket counts the calls and retries of a slice of the window.
+type budgetBucket struct {
+	epoch   int64
+	calls   float64
+	retries float64
+}
+
+// NewBudget creates a Budget allowing retries of at most ratio of the calls
+// made over window, plus the minimum set by WithMinRetries.
+func NewBudget(ratio float64, window time.Duration, opts ...BudgetOption) *Budget {
+	b := &Budget{
+		ratio:      ratio,
+		minRetries: 10,
+		bucketSize: max(window/budgetBuckets, 1),
+		now:        time.Now,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// bucket returns the current bucket, resetting it if it is stale.
+func (b *Budget) bucket() (*budgetBucket, int64) {
+	epoch := b.now().UnixNano() / int64(b.bucketSize)
+	bk := &b.buckets[epoch%budgetBuckets]
+	if bk.epoch != epoch {
+		*bk = budgetBucket{epoch: epoch}
+	}
+	return bk, epoch
+}
+
+// recordCall counts a call against the budget.
+func (b *Budget) recordCall() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	bk, _ := b.bucket()
+	bk.calls++
+}
+
+// tryRetry spends cost from the budget if it has room for it.
+func (b *Budget) tryRetry(cost float64) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	bk, epoch := b.bucket()
+	retries, allowed := b.totals(epoch)
+	if retries+cost > allowed {
+		return false
+	}
+	bk.retries += cost
+	return true
+}
+
+// Utilization returns the share of the retries allowed over the current
+// window that were spent, from 0 to 1.
+func (b *Budget) Utilization() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	_, epoch := b.bucket()
+	retries, allowed := b.totals(epoch)
+	if allowed <= 0 {
+		return 1
+	}
+	return min(retries/allowed, 1)
+}
+
+// totals returns the retries spent and allowed over the window ending at
+// epoch.
+func (b *Budget) totals(epoch int64) (retries, allowed float64) {
+	var calls float64
+	for _, x := range b.buckets {
+		if epoch-x.epoch < budgetBuckets {
+			calls += x.calls
+			retries += x.retries
+		}
+	}
+	return retries, b.ratio*calls + b.minRetries
+}
+
+// idle reports whether nothing was counted over the current window.
+func (b *Budget) idle() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	_, epoch := b.bucket()
+	for _, x := range b.buckets {
+		if epoch-x.epoch < budgetBuckets && (x.calls > 0 || x.retries > 0) {
+			return false
+		}
+	}
+	return true
+}
+
+// refund returns cost spent by tryRetry for a retry that was not made.
+func (b *Budget) refund(cost float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	bk, _ := b.bucket()
+	bk.retries = max(bk.retries-cost, 0)
+}
+
+// WithBudget spends b on every retry. Once b is exhausted, Do stops at the
+// first failed attempt and returns a BudgetExceededError wrapping its
+// error.
+func WithBudget(b *Budget) RetryOption {
+	return func(r *retrier) {
+		r.budget = b
+	}
+}
+
+// WithRetryQuota caps the retrier to n retries per sliding window, for
+// dependencies that contractually limit retry traffic. Unlike WithBudget,
+// the cap is absolute and specific to the retrier. Once it is reached, Do
+// stops at the first failed attempt and returns a BudgetExceededError.
+func WithRetryQuota(n int, window time.Duration) RetryOption {
+	return func(r *retrier) {
+		r.quota = NewBudget(0, window, WithMinRetries(n))
+	}
+}
+
+// BudgetStore holds one Budget per key, created on first use, so the
+// retries of one key, such as a noisy tenant, cannot consume the retry
+// capacity of the others. Budgets that counted nothing over a whole window
+// hold no state and are evicted, so the store does not grow with the keys
+// seen over its lifetime. It is safe for concurrent use.
+type BudgetStore struct {
+	ratio  float64
+	window time.Duration
+	opts   []BudgetOption
+	now    func() time.Time
+
+	mu      sync.Mutex
+	budgets map[string]*Budget
+	swept   time.Time
+}
+
+// NewBudgetStore creates a BudgetStore whose budgets are created with
+// NewBudget(ratio, window, opts...).
+func NewBudgetStore(ratio float64, window time.Duration, opts ...BudgetOption) *BudgetStore {
+	return &BudgetStore{
+		ratio:   ratio,
+		window:  window,
+		opts:    opts,
+		now:     time.Now,
+		budgets: make(map[string]*Budget),
+	}
+}
+
+// Budget returns the budget of key.
+func (s *BudgetStore) Budget(key string) *Budget {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now := s.now(); now.Sub(s.swept) >= s.window {
+		s.sweep()
+		s.swept = now
+	}
+	b, ok := s.budgets[key]
+	if !ok {
+		b = NewBudget(s.ratio, s.window, s.opts...)
+		b.now = s.now
+		s.budgets[key] = b
+	}
+	return b
+}
+
+// sweep evicts the idle budgets.
+func (s *BudgetStore) sweep() {
+	for key, b := range s.budgets {
+		if b.idle() {
+			delete(s.budgets, key)
+		}
+	}
+}
+
+// WithBudgetStore spends, for every call, the budget of s keyed by the
+// function set with WithBudgetKeyFunc, as WithBudget does with a single
+// budget. It replaces WithBudget.
+func WithBudgetStore(s *BudgetStore) RetryOption {
+	return func(r *retrier) {
+		r.budgetStore = s
+	}
+}
+
+// WithBudgetKeyFunc sets the function returning the key of the call's
+// budget in the store set with WithBudgetStore, e.g. the tenant ID carried
+// by ctx. Without it, every call uses the empty key.
+func WithBudgetKeyFunc(fn func(ctx context.Context) string) RetryOption {
+	return func(r *retrier) {
+		r.budgetKey = fn
+	}
+}
+
+// callBudget returns the budget of the call ctx belongs to, if any.
+func (r retrier) callBudget(ctx context.Context) *Budget {
+	if r.budgetStore == nil {
+		return r.budget
+	}
+	var key string
+	if r.budgetKey != nil {
+		key = r.budgetKey(ctx)
+	}
+	return r.budgetStore.Budget(key)
+}
+
+// SLOGate is implemented by providers of the service's SLO error budget,
+// to veto retries while the budget is nearly consumed.
+type SLOGate interface {
+	// AllowRetry reports whether a failed attempt of the call ctx belongs
+	// to may be retried.
+	AllowRetry(ctx context.Context) bool
+}
+
+// WithSLOGate consults g before every retry, trading retry success for
+// reduced load during brownouts. When g vetoes a retry, Do stops and
+// returns a BudgetExceededError wrapping the attempt error. Gates set by
+// repeated calls are consulted in order, after the budget and the quota,
+// and any of them can veto.
+func WithSLOGate(g SLOGate) RetryOption {
+	return func(r *retrier) {
+		r.sloGates = append(r.sloGates, g)
+	}
+}
+
+// WithAttemptCost makes every retry spend cost(attempt) from the budget set
+// by WithBudget instead of 1, where attempt is the zero-based number of the
+// attempt to be made, so expensive operations retry less than cheap ones
+// sharing the budget. The quota set by WithRetryQuota still counts retries.
+func WithAttemptCost(cost func(attempt int) float64) RetryOption {
+	return func(r *retrier) {
+		r.attemptCost = cost
+	}
+}
+
+// spendRetry reports whether the quota, the budget and the SLO gates allow
+// the given attempt to be made as a retry, spending from the budget and
+// quota if so. The gates are consulted last, since they may count the
+// retry in shared state that cannot be refunded, see
+// retryredis.RetryLimiter.
+func (r retrier) spendRetry(ctx context.Context, attempt int) bool {
+	if r.quota != nil && !r.quota.tryRetry(1) {
+		return false
+	}
+	cost := 1.0
+	if r.attemptCost != nil {
+		cost = r.attemptCost(attempt)
+	}
+	b := r.callBudget(ctx)
+	if b != nil && !b.tryRetry(cost) {
+		// The retry is not made, so it does not count against the quota.
+		r.refundRetry(nil, 0)
+		return false
+	}
+	for _, g := range r.sloGates {
+		if !g.AllowRetry(ctx) {
+			r.refundRetry(b, cost)
+			return false
+		}
+	}
+	return true
+}
+
+// refundRetry returns the retry spent from the quota and cost spent from
+// b, if not nil, for a retry that is not made.
+func (r retrier) refundRetry(b *Budget, cost float64) {
+	if r.quota != nil {
+		r.quota.refund(1)
+	}
+	if b != nil {
+		b.refund(cost)
+	}
+}
@@ -0,0 +1,205 @@
+package retry
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestBudget(ratio float64, window time.Duration, opts ...BudgetOption) (*Budget, *fakeClock) {
+	clock := &fakeClock{t: time.Unix(1000, 0)}
+	b := NewBudget(ratio, window, opts...)
+	b.now = clock.now
+	return b, clock
+}
+
+func TestBudget(t *testing.T) {
+	b, clock := newTestBudget(0.2, 10*time.Second, WithMinRetries(1))
+
+	for range 10 {
+		b.recordCall()
+	}
+	assert.True(t, b.tryRetry(1))
+	assert.True(t, b.tryRetry(1))
+	assert.True(t, b.tryRetry(1), "the minimum adds to the ratio")
+	assert.False(t, b.tryRetry(1))
+
+	clock.advance(5 * time.Second)
+	assert.False(t, b.tryRetry(1), "retries are still within the window")
+
+	clock.advance(5 * time.Second)
+	assert.True(t, b.tryRetry(1), "the window slid past the retries")
+}
+
+func TestWithBudget(t *testing.T) {
+	b, _ := newTestBudget(0, time.Minute, WithMinRetries(2))
+	r1 := New(WithMaxAttempts(3), WithBackoff(FixedBackoff{}), WithBudget(b))
+	r2 := New(WithMaxAttempts(3), WithBackoff(FixedBackoff{}), WithBudget(b))
+
+	calls := 0
+	fail := func(attempt int) error {
+		calls++
+		return errAlwaysFail
+	}
+
+	err := r1.Do(context.Background(), fail)
+	assert.True(t, isExhausted(err))
+	assert.Equal(t, 3, calls)
+
+	calls = 0
+	err = r2.Do(context.Background(), fail)
+	assert.ErrorIs(t, err, ErrBudgetExceeded)
+	assert.ErrorIs(t, err, errAlwaysFail)
+	assert.Equal(t, CodeBudgetExceeded, ErrorCode(err))
+	assert.Equal(t, 1, calls, "the budget is shared between retriers")
+
+	var e *BudgetExceededError
+	require.ErrorAs(t, err, &e)
+	data, jerr := json.Marshal(e)
+	require.NoError(t, jerr)
+	assert.JSONEq(t, `{"code":"retry.budget_exceeded","error":"retry budget exceeded: always fail","cause":{"message":"always fail","type":"*errors.errorString"}}`, string(data))
+}
+
+func TestWithRetryQuota(t *testing.T) {
+	r := New(WithMaxAttempts(3), WithBackoff(FixedBackoff{}), WithRetryQuota(3, time.Minute))
+
+	calls := 0
+	fail := func(attempt int) error {
+		calls++
+		return errAlwaysFail
+	}
+
+	for range 10 {
+		_ = r.Do(context.Background(), func(attempt int) error { return nil })
+	}
+	assert.True(t, isExhausted(r.Do(context.Background(), fail)))
+	assert.Equal(t, 3, calls)
+
+	calls = 0
+	err := r.Do(context.Background(), fail)
+	assert.ErrorIs(t, err, ErrBudgetExceeded)
+	assert.Equal(t, 2, calls, "the quota is absolute, not a ratio of calls")
+}
+
+func TestWithRetryQuota_BudgetRefund(t *testing.T) {
+	b, _ := newTestBudget(0, time.Minute, WithMinRetries(0))
+	r := New(WithMaxAttempts(2), WithBackoff(FixedBackoff{}), WithBudget(b), WithRetryQuota(1, time.Minute))
+
+	err := r.Do(context.Background(), func(attempt int) error { return errAlwaysFail })
+	assert.ErrorIs(t, err, ErrBudgetExceeded)
+	assert.True(t, r.(*retrier).quota.tryRetry(1), "a retry denied by the budget is refunded to the quota")
+}
+
+type sloGateFunc func(context.Context) bool
+
+func (f sloGateFunc) AllowRetry(ctx context.Context) bool { return f(ctx) }
+
+func TestWithSLOGate(t *testing.T) {
+	burning := false
+	b, _ := newTestBudget(0, time.Minute, WithMinRetries(100))
+	r := New(
+		WithMaxAttempts(3),
+		WithBackoff(FixedBackoff{}),
+		WithBudget(b),
+		WithSLOGate(sloGateFunc(func(context.Context) bool { return !burning })),
+	)
+
+	calls := 0
+	fail := func(attempt int) error {
+		calls++
+		return errAlwaysFail
+	}
+	assert.True(t, isExhausted(r.Do(context.Background(), fail)))
+	assert.Equal(t, 3, calls)
+
+	burning = true
+	calls = 0
+	err := r.Do(context.Background(), fail)
+	assert.ErrorIs(t, err, ErrBudgetExceeded)
+	assert.Equal(t, 1, calls)
+	assert.True(t, b.tryRetry(98), "vetoed retries do not spend the budget")
+}
+
+func TestWithAttemptCost(t *testing.T) {
+	b, _ := newTestBudget(0, time.Minute, WithMinRetries(10))
+	var costs []int
+	expensive := New(
+		WithMaxAttempts(0),
+		WithBackoff(FixedBackoff{}),
+		WithBudget(b),
+		WithAttemptCost(func(attempt int) float64 {
+			costs = append(costs, attempt)
+			return 4
+		}),
+	)
+	cheap := New(WithMaxAttempts(3), WithBackoff(FixedBackoff{}), WithBudget(b))
+
+	calls := 0
+	err := expensive.Do(context.Background(), func(attempt int) error {
+		calls++
+		return errAlwaysFail
+	})
+	assert.ErrorIs(t, err, ErrBudgetExceeded)
+	assert.Equal(t, 3, calls, "two retries of cost 4 fit in a budget of 10")
+	assert.Equal(t, []int{1, 2, 3}, costs)
+
+	calls = 0
+	_ = cheap.Do(context.Background(), func(attempt int) error {
+		calls++
+		return errAlwaysFail
+	})
+	assert.Equal(t, 3, calls, "the remaining budget serves two cheap retries")
+}
+
+type tenantKey struct{}
+
+func TestWithBudgetStore(t *testing.T) {
+	store := NewBudgetStore(0, time.Minute, WithMinRetries(2))
+	r := New(
+		WithMaxAttempts(3),
+		WithBackoff(FixedBackoff{}),
+		WithBudgetStore(store),
+		WithBudgetKeyFunc(func(ctx context.Context) string {
+			tenant, _ := ctx.Value(tenantKey{}).(string)
+			return tenant
+		}),
+	)
+
+	attempts := func(tenant string) int {
+		calls := 0
+		ctx := context.WithValue(context.Background(), tenantKey{}, tenant)
+		_ = r.Do(ctx, func(attempt int) error {
+			calls++
+			return errAlwaysFail
+		})
+		return calls
+	}
+
+	assert.Equal(t, 3, attempts("noisy"))
+	assert.Equal(t, 1, attempts("noisy"), "the noisy tenant exhausted its budget")
+	assert.Equal(t, 3, attempts("quiet"), "other tenants keep their budget")
+	assert.Same(t, store.Budget("quiet"), store.Budget("quiet"))
+}
+
+func TestBudgetStore_Eviction(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(1000, 0)}
+	store := NewBudgetStore(0.5, 10*time.Second)
+	store.now = clock.now
+
+	active, idle := store.Budget("active"), store.Budget("idle")
+	idle.recordCall()
+	for range 3 {
+		clock.advance(5 * time.Second)
+		active.recordCall()
+	}
+	assert.Same(t, active, store.Budget("active"), "budgets in use are kept")
+	assert.NotSame(t, idle, store.Budget("idle"), "budgets idle for a window are evicted")
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	assert.Len(t, store.budgets, 2)
+}
@@ -0,0 +1,79 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+)
+
+// ErrBulkheadFull is returned by Do calls rejected because the concurrency
+// limit set by WithMaxConcurrent is reached and no waiting slot is free.
+var ErrBulkheadFull = errors.New("bulkhead is full")
+
+// WithMaxConcurrent limits the retrier to n concurrent Do calls, so a slow
+// dependency cannot tie up every goroutine of the service. Up to
+// maxWaiting further calls wait for a free slot until their context is
+// done; the others fail immediately with ErrBulkheadFull. Rejected calls
+// make no attempt and are not observed by hooks or metrics.
+func WithMaxConcurrent(n, maxWaiting int) RetryOption {
+	return func(r *retrier) {
+		r.bulkhead = newBulkhead(n, maxWaiting)
+	}
+}
+
+// WithBulkheadStage returns a decorator limiting any func(ctx) error to n
+// concurrent executions, e.g. as a stage of a resilience pipeline. As with
+// WithMaxConcurrent, up to maxWaiting further executions wait for a free
+// slot, failing with the context error if it is done first, and the others
+// fail immediately with ErrBulkheadFull. The limit is shared by every
+// function the decorator is applied to.
+func WithBulkheadStage(n, maxWaiting int) func(f func(context.Context) error) func(context.Context) error {
+	b := newBulkhead(n, maxWaiting)
+	return func(f func(context.Context) error) func(context.Context) error {
+		return func(ctx context.Context) error {
+			if err := b.acquire(ctx); err != nil {
+				return err
+			}
+			defer b.release()
+			return f(ctx)
+		}
+	}
+}
+
+// bulkhead is a semaphore with a bounded wait queue.
+type bulkhead struct {
+	slots      chan struct{}
+	maxWaiting int64
+	waiting    atomic.Int64
+}
+
+func newBulkhead(n, maxWaiting int) *bulkhead {
+	return &bulkhead{
+		slots:      make(chan struct{}, max(n, 1)),
+		maxWaiting: int64(max(maxWaiting, 0)),
+	}
+}
+
+// acquire takes a slot, waiting for one if the queue has room.
+func (b *bulkhead) acquire(ctx context.Context) error {
+	select {
+	case b.slots <- struct{}{}:
+		return nil
+	default:
+	}
+
+	if b.waiting.Add(1) > b.maxWaiting {
+		b.waiting.Add(-1)
+		return ErrBulkheadFull
+	}
+	defer b.waiting.Add(-1)
+
+	select {
+	case b.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (b *bulkhead) release() { <-b.slots }
@@ -0,0 +1,94 @@
+package retry
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithMaxConcurrent(t *testing.T) {
+	r := New(WithMaxAttempts(1), WithMaxConcurrent(2, 1), WithStats())
+	b := r.(*retrier).bulkhead
+
+	release := make(chan struct{})
+	var wg sync.WaitGroup
+	errs := make(chan error, 3)
+	for range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- r.Do(context.Background(), func(attempt int) error {
+				<-release
+				return nil
+			})
+		}()
+	}
+
+	// Two calls run and the third one waits for a slot.
+	require.Eventually(t, func() bool {
+		return len(b.slots) == 2 && b.waiting.Load() == 1
+	}, time.Second, time.Millisecond)
+
+	err := r.Do(context.Background(), func(attempt int) error { return nil })
+	assert.ErrorIs(t, err, ErrBulkheadFull)
+
+	close(release)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, uint64(3), r.(StatsReporter).Stats().Calls, "rejected calls are not observed")
+}
+
+func TestWithMaxConcurrent_WaitCanceled(t *testing.T) {
+	r := New(WithMaxAttempts(1), WithMaxConcurrent(1, 1))
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	go func() {
+		_ = r.Do(context.Background(), func(attempt int) error {
+			close(started)
+			<-release
+			return nil
+		})
+	}()
+	<-started
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	err := r.Do(ctx, func(attempt int) error { return nil })
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestWithBulkheadStage(t *testing.T) {
+	stage := WithBulkheadStage(1, 0)
+	release := make(chan struct{})
+	started := make(chan struct{})
+	go func() {
+		_ = stage(func(context.Context) error {
+			close(started)
+			<-release
+			return nil
+		})(context.Background())
+	}()
+	<-started
+
+	calls := 0
+	err := stage(func(context.Context) error {
+		calls++
+		return nil
+	})(context.Background())
+	assert.ErrorIs(t, err, ErrBulkheadFull, "the limit is shared by the decorated functions")
+	assert.Zero(t, calls)
+
+	close(release)
+	require.Eventually(t, func() bool {
+		return stage(func(context.Context) error { return nil })(context.Background()) == nil
+	}, time.Second, time.Millisecond)
+}
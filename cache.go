@@ -0,0 +1,51 @@
+package retry
+
+import (
+	"sync"
+	"time"
+)
+
+// WithResultCache makes DoValue reuse the value of a successful call for
+// ttl instead of calling again, e.g. for token or metadata refresh paths.
+// The retrier caches a single value, so it should be dedicated to one
+// resource. Do and DoContext calls are not cached.
+func WithResultCache(ttl time.Duration) RetryOption {
+	return func(r *retrier) {
+		r.cache = &resultCache{ttl: ttl, now: time.Now}
+	}
+}
+
+// resultCache holds the last value returned by a successful call. A nil
+// *resultCache caches nothing.
+type resultCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	val     any
+	expires time.Time
+}
+
+// get returns the cached value if it has not expired.
+func (c *resultCache) get() (any, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.now().Before(c.expires) {
+		return nil, false
+	}
+	return c.val, true
+}
+
+// set caches v for the cache TTL.
+func (c *resultCache) set(v any) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.val = v
+	c.expires = c.now().Add(c.ttl)
+}
@@ -0,0 +1,68 @@
+package retry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithResultCache(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(0, 0)}
+	r := New(WithMaxAttempts(1), WithResultCache(time.Minute))
+	r.(*retrier).cache.now = clock.now
+
+	calls := 0
+	fetch := func(context.Context, int) (int, error) {
+		calls++
+		if calls == 2 {
+			return 0, errAlwaysFail
+		}
+		return calls, nil
+	}
+
+	v, err := DoValue(context.Background(), r, fetch)
+	require.NoError(t, err)
+	assert.Equal(t, 1, v)
+
+	clock.advance(30 * time.Second)
+	v, err = DoValue(context.Background(), r, fetch)
+	require.NoError(t, err)
+	assert.Equal(t, 1, v, "the value is reused within the TTL")
+	assert.Equal(t, 1, calls)
+
+	clock.advance(30 * time.Second)
+	_, err = DoValue(context.Background(), r, fetch)
+	assert.ErrorIs(t, err, errAlwaysFail, "failures are not cached")
+
+	v, err = DoValue(context.Background(), r, fetch)
+	require.NoError(t, err)
+	assert.Equal(t, 3, v)
+
+	assert.NoError(t, r.Do(context.Background(), func(int) error { return nil }))
+}
+
+func TestWithResultCache_OtherType(t *testing.T) {
+	r := New(WithResultCache(time.Minute))
+
+	s, err := DoValue(context.Background(), r, func(context.Context, int) (string, error) {
+		return "token", nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "token", s)
+
+	n, err := DoValue(context.Background(), r, func(context.Context, int) (int, error) {
+		return 42, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 42, n, "a cached value of another type is not used")
+
+	var e error
+	e, err = DoValue(context.Background(), r, func(context.Context, int) (error, error) {
+		return nil, nil
+	})
+	require.NoError(t, err)
+	assert.Nil(t, e)
+}
@@ -0,0 +1,111 @@
+package retry
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+)
+
+// defaultChunkSize is the size of the chunks of UploadChunks.
+const defaultChunkSize = 8 << 20
+
+// Chunk is a piece of the data uploaded by UploadChunks.
+type Chunk struct {
+	// Index is the position of the chunk, from 0.
+	Index int
+	// Offset is the position of the first byte of the chunk in the data.
+	Offset int64
+	// Data is the content of the chunk. It is only valid until the upload
+	// function and the callback of WithOnChunk return.
+	Data []byte
+	// Checksum is the checksum of Data, SHA-256 unless set with
+	// WithChunkChecksum.
+	Checksum []byte
+}
+
+// ChunkOption configures UploadChunks.
+type ChunkOption func(*chunker)
+
+// WithChunkSize sets the size of the chunks. The last chunk may be
+// smaller. The default is 8 MiB.
+func WithChunkSize(n int) ChunkOption {
+	return func(c *chunker) {
+		c.size = n
+	}
+}
+
+// WithChunkChecksum sets the hash computing the checksum of the chunks.
+func WithChunkChecksum(newHash func() hash.Hash) ChunkOption {
+	return func(c *chunker) {
+		c.newHash = newHash
+	}
+}
+
+// WithOnChunk calls fn after every chunk is uploaded, e.g. to record the
+// progress of a resumable upload.
+func WithOnChunk(fn func(Chunk)) ChunkOption {
+	return func(c *chunker) {
+		c.onChunk = fn
+	}
+}
+
+type chunker struct {
+	size    int
+	newHash func() hash.Hash
+	onChunk func(Chunk)
+}
+
+// UploadChunks splits src into chunks and passes them in order to upload,
+// retrying every chunk independently with r, so a failure only resends
+// the chunk that failed instead of the whole data, as resumable uploads
+// to object stores do. Chunks are read as they are uploaded; src is not
+// read in advance.
+//
+// UploadChunks returns nil once src is exhausted and every chunk is
+// uploaded, the error of src if reading it fails, or the error of r for
+// the first chunk it gives up on.
+func UploadChunks(ctx context.Context, r Retrier, src io.Reader, upload func(context.Context, Chunk) error, opts ...ChunkOption) error {
+	c := chunker{size: defaultChunkSize, newHash: sha256.New}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	if c.size <= 0 {
+		c.size = defaultChunkSize
+	}
+
+	buf := make([]byte, c.size)
+	var offset int64
+	for index := 0; ; index++ {
+		n, err := io.ReadFull(src, buf)
+		if n == 0 {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+			return err
+		}
+
+		h := c.newHash()
+		h.Write(buf[:n])
+		chunk := Chunk{Index: index, Offset: offset, Data: buf[:n], Checksum: h.Sum(nil)}
+		if err := doContext(ctx, r, func(ctx context.Context, _ int) error {
+			return upload(ctx, chunk)
+		}); err != nil {
+			return fmt.Errorf("chunk %d: %w", index, err)
+		}
+		if c.onChunk != nil {
+			c.onChunk(chunk)
+		}
+
+		offset += int64(n)
+		if err != nil {
+			// The last chunk was short.
+			return nil
+		}
+	}
+}
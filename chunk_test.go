@@ -0,0 +1,69 @@
+package retry
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"errors"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUploadChunks(t *testing.T) {
+	r := New(WithMaxAttempts(3), WithBackoff(FixedBackoff{}))
+
+	var uploaded []string
+	var progress []int64
+	calls := 0
+	err := UploadChunks(context.Background(), r, strings.NewReader("abcdefghij"), func(_ context.Context, c Chunk) error {
+		calls++
+		if c.Index == 1 && calls == 2 {
+			return errAlwaysFail // the second chunk fails once
+		}
+		sum := sha256.Sum256(c.Data)
+		assert.Equal(t, sum[:], c.Checksum)
+		uploaded = append(uploaded, string(c.Data))
+		return nil
+	},
+		WithChunkSize(4),
+		WithOnChunk(func(c Chunk) { progress = append(progress, c.Offset+int64(len(c.Data))) }),
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 4, calls, "only the failed chunk is sent again")
+	assert.Equal(t, []string{"abcd", "efgh", "ij"}, uploaded)
+	assert.Equal(t, []int64{4, 8, 10}, progress)
+}
+
+func TestUploadChunks_Checksum(t *testing.T) {
+	var sums [][]byte
+	err := UploadChunks(context.Background(), NoRetry(), strings.NewReader("abcd"), func(_ context.Context, c Chunk) error {
+		sums = append(sums, c.Checksum)
+		return nil
+	}, WithChunkSize(4), WithChunkChecksum(md5.New))
+
+	assert.NoError(t, err)
+	sum := md5.Sum([]byte("abcd"))
+	assert.Equal(t, [][]byte{sum[:]}, sums, "an exact multiple of the chunk size has no empty chunk")
+}
+
+func TestUploadChunks_Errors(t *testing.T) {
+	r := New(WithMaxAttempts(2), WithBackoff(FixedBackoff{}))
+	upload := func(_ context.Context, c Chunk) error {
+		if c.Index == 1 {
+			return errAlwaysFail
+		}
+		return nil
+	}
+
+	err := UploadChunks(context.Background(), r, strings.NewReader("abcdefgh"), upload, WithChunkSize(4))
+	assert.ErrorIs(t, err, errAlwaysFail)
+	assert.ErrorContains(t, err, "chunk 1")
+
+	errRead := errors.New("read failed")
+	err = UploadChunks(context.Background(), r, iotest.ErrReader(errRead), upload)
+	assert.ErrorIs(t, err, errRead)
+}
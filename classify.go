@@ -0,0 +1,138 @@
+package retry
+
+import (
+	"errors"
+	"regexp"
+)
+
+// isAny reports whether err matches any of targets according to errors.Is.
+func isAny(err error, targets []error) bool {
+	for _, target := range targets {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// RetryOnType returns an IsRetryableFunc that retries only errors whose
+// chain contains an error of type T, according to errors.As.
+func RetryOnType[T error]() IsRetryableFunc {
+	return func(err error) bool {
+		return isType[T](err)
+	}
+}
+
+// RetryOnTypes returns an IsRetryableFunc that retries only errors whose
+// chain contains an error of type T1 or T2, according to errors.As.
+func RetryOnTypes[T1, T2 error]() IsRetryableFunc {
+	return func(err error) bool {
+		return isType[T1](err) || isType[T2](err)
+	}
+}
+
+// StopOnType returns an IsRetryableFunc that retries every error except
+// those whose chain contains an error of type T.
+func StopOnType[T error]() IsRetryableFunc {
+	return func(err error) bool {
+		return err != nil && !isType[T](err)
+	}
+}
+
+// StopOnTypes returns an IsRetryableFunc that retries every error except
+// those whose chain contains an error of type T1 or T2.
+func StopOnTypes[T1, T2 error]() IsRetryableFunc {
+	return func(err error) bool {
+		return err != nil && !isType[T1](err) && !isType[T2](err)
+	}
+}
+
+// isType reports whether err's chain contains an error of type T.
+func isType[T error](err error) bool {
+	var target T
+	return errors.As(err, &target)
+}
+
+// AllOf returns an IsRetryableFunc that retries only when every one of
+// fns deems the error retryable. With no fns it retries any non-nil error.
+func AllOf(fns ...IsRetryableFunc) IsRetryableFunc {
+	return func(err error) bool {
+		if err == nil {
+			return false
+		}
+		for _, fn := range fns {
+			if !fn(err) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// AnyOf returns an IsRetryableFunc that retries when at least one of fns
+// deems the error retryable. With no fns it never retries.
+func AnyOf(fns ...IsRetryableFunc) IsRetryableFunc {
+	return func(err error) bool {
+		for _, fn := range fns {
+			if fn(err) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not returns an IsRetryableFunc that inverts fn for non-nil errors.
+func Not(fn IsRetryableFunc) IsRetryableFunc {
+	return func(err error) bool {
+		return err != nil && !fn(err)
+	}
+}
+
+// RetryOnMessage returns an IsRetryableFunc that retries only errors whose
+// message matches at least one of the regular expression patterns.
+// It panics if a pattern cannot be compiled.
+func RetryOnMessage(patterns ...string) IsRetryableFunc {
+	res := make([]*regexp.Regexp, len(patterns))
+	for i, p := range patterns {
+		res[i] = regexp.MustCompile(p)
+	}
+
+	return func(err error) bool {
+		if err == nil {
+			return false
+		}
+		msg := err.Error()
+		for _, re := range res {
+			if re.MatchString(msg) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Vote is a weighted sub-classifier of ScoredClassifier.
+type Vote struct {
+	Classify IsRetryableFunc
+	Weight   float64
+}
+
+// ScoredClassifier returns an IsRetryableFunc that sums the weights of the
+// votes deeming an error retryable and retries when the sum reaches
+// threshold. Negative weights let a heuristic count against retrying.
+func ScoredClassifier(threshold float64, votes ...Vote) IsRetryableFunc {
+	return func(err error) bool {
+		if err == nil {
+			return false
+		}
+
+		var score float64
+		for _, v := range votes {
+			if v.Classify(err) {
+				score += v.Weight
+			}
+		}
+		return score >= threshold
+	}
+}
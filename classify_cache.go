@@ -0,0 +1,66 @@
+package retry
+
+import (
+	"reflect"
+	"sync"
+)
+
+// CachedClassifier memoizes the decisions of fn, for hot paths where fn does
+// non-trivial work such as regex matching, reflection or status parsing.
+//
+// Decisions are keyed by the dynamic type and message of the error, so fn
+// must classify errors with the same type and message identically.
+// At most size decisions are kept; when the cache is full an arbitrary
+// entry is evicted. A size of 0 or less means no limit.
+//
+// The returned function is safe for concurrent use.
+func CachedClassifier(fn IsRetryableFunc, size int) IsRetryableFunc {
+	c := &classifierCache{
+		fn:      fn,
+		size:    size,
+		entries: make(map[classifierCacheKey]bool),
+	}
+	return c.isRetryable
+}
+
+type classifierCacheKey struct {
+	typ reflect.Type
+	msg string
+}
+
+type classifierCache struct {
+	fn   IsRetryableFunc
+	size int
+
+	mu      sync.Mutex
+	entries map[classifierCacheKey]bool
+}
+
+func (c *classifierCache) isRetryable(err error) bool {
+	if err == nil {
+		return c.fn(err)
+	}
+
+	key := classifierCacheKey{typ: reflect.TypeOf(err), msg: err.Error()}
+
+	c.mu.Lock()
+	decision, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok {
+		return decision
+	}
+
+	decision = c.fn(err)
+
+	c.mu.Lock()
+	if c.size > 0 && len(c.entries) >= c.size {
+		for k := range c.entries {
+			delete(c.entries, k)
+			break
+		}
+	}
+	c.entries[key] = decision
+	c.mu.Unlock()
+
+	return decision
+}
@@ -0,0 +1,47 @@
+package retry
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCachedClassifier(t *testing.T) {
+	calls := 0
+	classify := CachedClassifier(func(err error) bool {
+		calls++
+		return errors.Is(err, errAlwaysFail)
+	}, 2)
+
+	assert.True(t, classify(fmt.Errorf("op: %w", errAlwaysFail)))
+	assert.True(t, classify(fmt.Errorf("op: %w", errAlwaysFail)))
+	assert.Equal(t, 1, calls, "equal type and message should hit the cache")
+
+	assert.False(t, classify(errCustom))
+	assert.False(t, classify(errCustom))
+	assert.Equal(t, 2, calls)
+
+	assert.True(t, classify(errAlwaysFail), "a new error should be classified")
+	assert.Equal(t, 3, calls)
+
+	c := CachedClassifier(func(err error) bool { return false }, 0)
+	assert.False(t, c(nil))
+}
+
+func TestCachedClassifier_Eviction(t *testing.T) {
+	calls := 0
+	classify := CachedClassifier(func(err error) bool {
+		calls++
+		return true
+	}, 1)
+
+	classify(errAlwaysFail)
+	classify(errCustom)
+	classify(errCustom)
+	assert.Equal(t, 2, calls)
+
+	classify(errAlwaysFail)
+	assert.Equal(t, 3, calls, "evicted entry should be recomputed")
+}
@@ -0,0 +1,29 @@
+package retry
+
+// IsErrnoTransient reports whether err is a syscall-level failure that
+// typically succeeds when repeated: an interrupted call, a resource that is
+// temporarily unavailable, or a busy device. File descriptor exhaustion is
+// not retried; use ErrnoClassifier to opt into it.
+func IsErrnoTransient(err error) bool {
+	return ErrnoClassifier(false)(err)
+}
+
+// ErrnoClassifier returns an IsRetryableFunc for syscall-level errors
+// found anywhere in the error chain. If retryFileLimits is true,
+// exhaustion of process or system file descriptors (EMFILE, ENFILE) is
+// retried as well, which is useful when descriptors are released by
+// concurrent work.
+//
+// The set of recognized errors depends on the platform; on platforms
+// without errno-style errors nothing is retried.
+func ErrnoClassifier(retryFileLimits bool) IsRetryableFunc {
+	return func(err error) bool {
+		if err == nil {
+			return false
+		}
+		if isTransientErrno(err) {
+			return true
+		}
+		return retryFileLimits && isFileLimitErrno(err)
+	}
+}
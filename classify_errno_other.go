@@ -0,0 +1,9 @@
+//go:build !unix && !windows
+
+package retry
+
+func isTransientErrno(err error) bool { return false }
+
+func isFileLimitErrno(err error) bool { return false }
+
+func isConnErrno(err error) bool { return false }
@@ -0,0 +1,39 @@
+//go:build unix
+
+package retry
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrnoClassifier(t *testing.T) {
+	tests := []struct {
+		name            string
+		err             error
+		retryFileLimits bool
+		want            bool
+	}{
+		{"nil", nil, true, false},
+		{"eintr", os.NewSyscallError("read", syscall.EINTR), false, true},
+		{"eagain", &os.PathError{Op: "open", Path: "/tmp/x", Err: syscall.EAGAIN}, false, true},
+		{"ebusy", fmt.Errorf("mount: %w", syscall.EBUSY), false, true},
+		{"emfile default", os.NewSyscallError("accept", syscall.EMFILE), false, false},
+		{"emfile configured", os.NewSyscallError("accept", syscall.EMFILE), true, true},
+		{"enfile configured", syscall.ENFILE, true, true},
+		{"enoent", syscall.ENOENT, true, false},
+		{"plain error", errCustom, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ErrnoClassifier(tt.retryFileLimits)(tt.err))
+		})
+	}
+
+	assert.True(t, IsErrnoTransient(syscall.EINTR))
+}
@@ -0,0 +1,46 @@
+//go:build windows
+
+package retry
+
+import (
+	"errors"
+	"syscall"
+)
+
+// Windows error codes not exported by the syscall package.
+const (
+	errorTooManyOpenFiles  = syscall.Errno(4)
+	errorSharingViolation  = syscall.Errno(32)
+	errorLockViolation     = syscall.Errno(33)
+	errorBusy              = syscall.Errno(170)
+	wsaeintr               = syscall.Errno(10004)
+	wsaewouldblock         = syscall.Errno(10035)
+	wsaeTooManyOpenSockets = syscall.Errno(10024)
+	wsaeconnrefused        = syscall.Errno(10061)
+)
+
+func isTransientErrno(err error) bool {
+	return errors.Is(err, syscall.EINTR) ||
+		errors.Is(err, syscall.EAGAIN) ||
+		errors.Is(err, syscall.EBUSY) ||
+		errors.Is(err, errorSharingViolation) ||
+		errors.Is(err, errorLockViolation) ||
+		errors.Is(err, errorBusy) ||
+		errors.Is(err, wsaeintr) ||
+		errors.Is(err, wsaewouldblock)
+}
+
+func isFileLimitErrno(err error) bool {
+	return errors.Is(err, syscall.EMFILE) ||
+		errors.Is(err, syscall.ENFILE) ||
+		errors.Is(err, errorTooManyOpenFiles) ||
+		errors.Is(err, wsaeTooManyOpenSockets)
+}
+
+func isConnErrno(err error) bool {
+	return errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, syscall.WSAECONNRESET) ||
+		errors.Is(err, wsaeconnrefused)
+}
@@ -0,0 +1,126 @@
+package retry
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StatusCoder is implemented by errors that carry an HTTP status code.
+type StatusCoder interface {
+	StatusCode() int
+}
+
+// HTTPStatusClassifier returns an IsRetryableFunc that retries errors
+// carrying one of the retryOn HTTP status codes via StatusCoder.
+// Errors without a status code are not retried.
+//
+// With no codes it uses the default set: 429 Too Many Requests,
+// 500 Internal Server Error, 502 Bad Gateway, 503 Service Unavailable
+// and 504 Gateway Timeout.
+func HTTPStatusClassifier(retryOn ...int) IsRetryableFunc {
+	if len(retryOn) == 0 {
+		retryOn = defaultRetryableStatusCodes()
+	}
+
+	return func(err error) bool {
+		code, ok := statusCode(err)
+		return ok && slices.Contains(retryOn, code)
+	}
+}
+
+// defaultRetryableStatusCodes returns the HTTP status codes retried by default.
+func defaultRetryableStatusCodes() []int {
+	return []int{
+		http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout,
+	}
+}
+
+// statusCode extracts the HTTP status code from err's chain, if any.
+func statusCode(err error) (int, bool) {
+	var sc StatusCoder
+	if !errors.As(err, &sc) {
+		return 0, false
+	}
+	return sc.StatusCode(), true
+}
+
+// HTTPError describes a failed HTTP response.
+//
+// It implements StatusCoder, so it works with HTTPStatusClassifier, and
+// DelayHinter, so a server-provided Retry-After drives the wait before
+// the next attempt.
+type HTTPError struct {
+	Code       int
+	Status     string
+	RetryAfter time.Duration
+}
+
+// NewHTTPError builds an HTTPError from resp, parsing its rate-limit
+// headers with ParseRetryAfter.
+func NewHTTPError(resp *http.Response) *HTTPError {
+	e := &HTTPError{Code: resp.StatusCode, Status: resp.Status}
+	if d, ok := ParseRetryAfter(resp, time.Now()); ok {
+		e.RetryAfter = d
+	}
+	return e
+}
+
+func (e *HTTPError) Error() string {
+	if e.Status != "" {
+		return fmt.Sprintf("http status %s", e.Status)
+	}
+	return fmt.Sprintf("http status %d", e.Code)
+}
+
+// StatusCode returns the HTTP status code of the response.
+func (e *HTTPError) StatusCode() int { return e.Code }
+
+// RetryDelay returns the server-requested delay, if one was provided.
+func (e *HTTPError) RetryDelay() (time.Duration, bool) { return e.RetryAfter, e.RetryAfter > 0 }
+
+// ParseRetryAfter extracts the server-requested delay from resp relative
+// to now. It understands:
+//   - Retry-After as delay seconds or an HTTP date
+//   - X-RateLimit-Reset as delay seconds or a Unix timestamp, used only
+//     when the response is rate limited (429, or X-RateLimit-Remaining is 0)
+//
+// Retry-After takes precedence. Dates in the past yield a zero delay.
+func ParseRetryAfter(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if v := strings.TrimSpace(resp.Header.Get("Retry-After")); v != "" {
+		if secs, err := strconv.ParseInt(v, 10, 64); err == nil && secs >= 0 {
+			return time.Duration(secs) * time.Second, true
+		}
+		if t, err := http.ParseTime(v); err == nil {
+			return max(t.Sub(now), 0), true
+		}
+	}
+
+	rateLimited := resp.StatusCode == http.StatusTooManyRequests ||
+		strings.TrimSpace(resp.Header.Get("X-RateLimit-Remaining")) == "0"
+	if !rateLimited {
+		return 0, false
+	}
+
+	if v := strings.TrimSpace(resp.Header.Get("X-RateLimit-Reset")); v != "" {
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil && n >= 0 {
+			if n > unixTimestampThreshold {
+				return max(time.Unix(n, 0).Sub(now), 0), true
+			}
+			return time.Duration(n) * time.Second, true
+		}
+	}
+	return 0, false
+}
+
+// unixTimestampThreshold separates X-RateLimit-Reset values given as
+// delay seconds from those given as Unix timestamps (roughly 2001-09-09).
+const unixTimestampThreshold = 1_000_000_000
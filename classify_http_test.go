@@ -0,0 +1,91 @@
+package retry
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type statusError int
+
+func (e statusError) Error() string   { return fmt.Sprintf("status %d", int(e)) }
+func (e statusError) StatusCode() int { return int(e) }
+
+func TestHTTPStatusClassifier(t *testing.T) {
+	tests := []struct {
+		name    string
+		retryOn []int
+		err     error
+		want    bool
+	}{
+		{"default 429", nil, statusError(http.StatusTooManyRequests), true},
+		{"default 503 wrapped", nil, fmt.Errorf("call: %w", statusError(http.StatusServiceUnavailable)), true},
+		{"default 501", nil, statusError(http.StatusNotImplemented), false},
+		{"default 404", nil, statusError(http.StatusNotFound), false},
+		{"custom 409", []int{http.StatusConflict}, statusError(http.StatusConflict), true},
+		{"custom excludes default", []int{http.StatusConflict}, statusError(http.StatusBadGateway), false},
+		{"no status", nil, errCustom, false},
+		{"nil", nil, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, HTTPStatusClassifier(tt.retryOn...)(tt.err))
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		code    int
+		headers map[string]string
+		want    time.Duration
+		wantOK  bool
+	}{
+		{"no headers", http.StatusTooManyRequests, nil, 0, false},
+		{"retry-after seconds", http.StatusServiceUnavailable, map[string]string{"Retry-After": "120"}, 2 * time.Minute, true},
+		{"retry-after date", http.StatusTooManyRequests, map[string]string{"Retry-After": now.Add(30 * time.Second).Format(http.TimeFormat)}, 30 * time.Second, true},
+		{"retry-after past date", http.StatusTooManyRequests, map[string]string{"Retry-After": now.Add(-time.Minute).Format(http.TimeFormat)}, 0, true},
+		{"retry-after invalid", http.StatusServiceUnavailable, map[string]string{"Retry-After": "soon"}, 0, false},
+		{"reset delta", http.StatusTooManyRequests, map[string]string{"X-RateLimit-Reset": "5"}, 5 * time.Second, true},
+		{"reset timestamp", http.StatusTooManyRequests, map[string]string{"X-RateLimit-Reset": fmt.Sprint(now.Add(time.Minute).Unix())}, time.Minute, true},
+		{"reset remaining zero", http.StatusForbidden, map[string]string{"X-RateLimit-Reset": "7", "X-RateLimit-Remaining": "0"}, 7 * time.Second, true},
+		{"reset not rate limited", http.StatusInternalServerError, map[string]string{"X-RateLimit-Reset": "7"}, 0, false},
+		{"retry-after precedence", http.StatusTooManyRequests, map[string]string{"Retry-After": "1", "X-RateLimit-Reset": "9"}, time.Second, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: tt.code, Header: http.Header{}}
+			for k, v := range tt.headers {
+				resp.Header.Set(k, v)
+			}
+
+			got, ok := ParseRetryAfter(resp, now)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewHTTPError(t *testing.T) {
+	resp := &http.Response{
+		StatusCode: http.StatusTooManyRequests,
+		Status:     "429 Too Many Requests",
+		Header:     http.Header{"Retry-After": []string{"3"}},
+	}
+
+	err := fmt.Errorf("fetch: %w", NewHTTPError(resp))
+	assert.EqualError(t, err, "fetch: http status 429 Too Many Requests")
+	assert.True(t, HTTPStatusClassifier()(err))
+
+	d, ok := delayHint(err)
+	assert.True(t, ok)
+	assert.Equal(t, 3*time.Second, d)
+}
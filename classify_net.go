@@ -0,0 +1,112 @@
+package retry
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net"
+)
+
+// IsNetworkTransient reports whether err is a transient network failure
+// worth retrying: a net.Error timeout, a connection reset or refused,
+// a broken pipe, or an unexpected EOF mid-stream.
+//
+// Context cancellation and deadline errors are not considered transient,
+// even though context.DeadlineExceeded reports itself as a timeout.
+func IsNetworkTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	if errors.Is(err, io.ErrUnexpectedEOF) || isConnErrno(err) {
+		return true
+	}
+
+	var ne net.Error
+	return errors.As(err, &ne) && ne.Timeout()
+}
+
+// IsDNSTransient reports whether err is a *net.DNSError caused by a
+// timeout or a temporary resolver failure such as SERVFAIL.
+// Lookups of names that do not exist (NXDOMAIN) are not retried;
+// use DNSClassifier to opt into retrying them.
+func IsDNSTransient(err error) bool {
+	return DNSClassifier(false)(err)
+}
+
+// DNSClassifier returns an IsRetryableFunc that retries *net.DNSError
+// timeouts and temporary failures. If retryNotFound is true, lookups of
+// names that do not exist are retried as well, which helps while records
+// are still propagating. Errors that are not DNS errors are not retried.
+func DNSClassifier(retryNotFound bool) IsRetryableFunc {
+	return func(err error) bool {
+		var de *net.DNSError
+		if !errors.As(err, &de) {
+			return false
+		}
+		if de.IsNotFound {
+			return retryNotFound
+		}
+		return de.IsTimeout || de.IsTemporary
+	}
+}
+
+// IsTLSTransient reports whether err is a TLS handshake failure worth
+// retrying: a handshake timeout, a connection reset or an unexpected EOF
+// while negotiating. Certificate validation failures are never transient.
+func IsTLSTransient(err error) bool {
+	if err == nil || IsCertificateError(err) {
+		return false
+	}
+	return errors.Is(err, io.EOF) || IsNetworkTransient(err)
+}
+
+// IsCertificateError reports whether err is caused by certificate
+// validation: an unknown authority, an invalid or expired certificate,
+// a hostname mismatch, or a certificate-related TLS alert.
+//
+// It can be combined with Not to retry everything except bad certificates:
+//
+//	retry.WithIsRetryableFunc(retry.Not(retry.IsCertificateError))
+func IsCertificateError(err error) bool {
+	var (
+		verifyErr    *tls.CertificateVerificationError
+		authorityErr x509.UnknownAuthorityError
+		invalidErr   x509.CertificateInvalidError
+		hostnameErr  x509.HostnameError
+		alertErr     tls.AlertError
+	)
+
+	switch {
+	case errors.As(err, &verifyErr),
+		errors.As(err, &authorityErr),
+		errors.As(err, &invalidErr),
+		errors.As(err, &hostnameErr):
+		return true
+	case errors.As(err, &alertErr):
+		return isCertificateAlert(alertErr)
+	}
+	return false
+}
+
+// isCertificateAlert reports whether a TLS alert received from the peer
+// signals that it rejected our certificate.
+// Alert codes are defined in RFC 8446, section 6.
+func isCertificateAlert(a tls.AlertError) bool {
+	switch a {
+	case 42, // bad_certificate
+		43,  // unsupported_certificate
+		44,  // certificate_revoked
+		45,  // certificate_expired
+		46,  // certificate_unknown
+		48,  // unknown_ca
+		116: // certificate_required
+		return true
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package retry
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type timeoutError struct{ timeout bool }
+
+func (e timeoutError) Error() string   { return "i/o timeout" }
+func (e timeoutError) Timeout() bool   { return e.timeout }
+func (e timeoutError) Temporary() bool { return false }
+
+func TestIsNetworkTransient(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"plain error", errCustom, false},
+		{"unexpected eof", fmt.Errorf("read body: %w", io.ErrUnexpectedEOF), true},
+		{"net timeout", timeoutError{timeout: true}, true},
+		{"net non-timeout", timeoutError{timeout: false}, false},
+		{"deadline exceeded", os.ErrDeadlineExceeded, true},
+		{"context deadline", fmt.Errorf("call: %w", context.DeadlineExceeded), false},
+		{"context canceled", context.Canceled, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsNetworkTransient(tt.err))
+		})
+	}
+}
+
+func TestDNSClassifier(t *testing.T) {
+	servfail := &net.DNSError{Err: "server misbehaving", Name: "example.com", IsTemporary: true}
+	timeout := &net.DNSError{Err: "i/o timeout", Name: "example.com", IsTimeout: true}
+	nxdomain := &net.DNSError{Err: "no such host", Name: "missing.example", IsNotFound: true}
+	permanent := &net.DNSError{Err: "invalid name", Name: "bad..name"}
+
+	tests := []struct {
+		name          string
+		err           error
+		retryNotFound bool
+		want          bool
+	}{
+		{"servfail", servfail, false, true},
+		{"timeout", fmt.Errorf("lookup: %w", timeout), false, true},
+		{"nxdomain", nxdomain, false, false},
+		{"nxdomain configured", nxdomain, true, true},
+		{"permanent", permanent, true, false},
+		{"not dns", errCustom, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, DNSClassifier(tt.retryNotFound)(tt.err))
+		})
+	}
+
+	assert.True(t, IsDNSTransient(servfail))
+	assert.False(t, IsDNSTransient(nxdomain))
+}
+
+func TestTLSClassifiers(t *testing.T) {
+	verifyErr := &tls.CertificateVerificationError{Err: x509.UnknownAuthorityError{}}
+	hostnameErr := x509.HostnameError{Host: "example.com", Certificate: &x509.Certificate{}}
+
+	tests := []struct {
+		name      string
+		err       error
+		transient bool
+		cert      bool
+	}{
+		{"nil", nil, false, false},
+		{"verification failure", fmt.Errorf("tls: %w", verifyErr), false, true},
+		{"hostname mismatch", hostnameErr, false, true},
+		{"expired alert", tls.AlertError(45), false, true},
+		{"other alert", tls.AlertError(40), false, false},
+		{"handshake timeout", timeoutError{timeout: true}, true, false},
+		{"eof during handshake", fmt.Errorf("handshake: %w", io.EOF), true, false},
+		{"plain error", errCustom, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.transient, IsTLSTransient(tt.err))
+			assert.Equal(t, tt.cert, IsCertificateError(tt.err))
+		})
+	}
+}
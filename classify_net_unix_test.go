@@ -0,0 +1,36 @@
+//go:build unix
+
+package retry
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsNetworkTransient_Errno(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"connection reset", &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}},
+		{"connection refused", &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}},
+		{"broken pipe", fmt.Errorf("write: %w", syscall.EPIPE)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.True(t, IsNetworkTransient(tt.err))
+		})
+	}
+}
+
+func TestIsTLSTransient_Errno(t *testing.T) {
+	resetErr := &net.OpError{Op: "remote error", Net: "tcp", Err: syscall.ECONNRESET}
+	assert.True(t, IsTLSTransient(resetErr))
+	assert.False(t, IsCertificateError(resetErr))
+}
@@ -0,0 +1,127 @@
+package retry
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type tempError struct{}
+
+func (tempError) Error() string { return "temporary" }
+
+type fatalError struct{ reason string }
+
+func (e *fatalError) Error() string { return "fatal: " + e.reason }
+
+func TestTypeClassifiers(t *testing.T) {
+	temp := fmt.Errorf("wrapped: %w", tempError{})
+	fatal := fmt.Errorf("wrapped: %w", &fatalError{reason: "bad input"})
+
+	tests := []struct {
+		name     string
+		classify IsRetryableFunc
+		err      error
+		want     bool
+	}{
+		{"retry on type match", RetryOnType[tempError](), temp, true},
+		{"retry on type mismatch", RetryOnType[tempError](), fatal, false},
+		{"retry on types second", RetryOnTypes[tempError, *fatalError](), fatal, true},
+		{"retry on types none", RetryOnTypes[tempError, *fatalError](), errCustom, false},
+		{"stop on type match", StopOnType[*fatalError](), fatal, false},
+		{"stop on type mismatch", StopOnType[*fatalError](), temp, true},
+		{"stop on types first", StopOnTypes[tempError, *fatalError](), temp, false},
+		{"stop on types none", StopOnTypes[tempError, *fatalError](), errCustom, true},
+		{"stop on type nil", StopOnType[*fatalError](), nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.classify(tt.err))
+		})
+	}
+}
+
+func TestCombinators(t *testing.T) {
+	always := func(error) bool { return true }
+	never := func(error) bool { return false }
+
+	tests := []struct {
+		name     string
+		classify IsRetryableFunc
+		err      error
+		want     bool
+	}{
+		{"all of true", AllOf(always, always), errCustom, true},
+		{"all of false", AllOf(always, never), errCustom, false},
+		{"all of empty", AllOf(), errCustom, true},
+		{"all of nil error", AllOf(always), nil, false},
+		{"any of true", AnyOf(never, always), errCustom, true},
+		{"any of false", AnyOf(never, never), errCustom, false},
+		{"any of empty", AnyOf(), errCustom, false},
+		{"not", Not(never), errCustom, true},
+		{"not nil error", Not(never), nil, false},
+		{
+			"layered policy",
+			AllOf(RetryOnType[tempError](), StopOnType[*fatalError]()),
+			fmt.Errorf("%w: %w", tempError{}, &fatalError{}),
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.classify(tt.err))
+		})
+	}
+}
+
+func TestRetryOnMessage(t *testing.T) {
+	classify := RetryOnMessage(`connection (reset|refused)`, `(?i)^timeout`)
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"first pattern", fmt.Errorf("dial: connection refused"), true},
+		{"second pattern", fmt.Errorf("TIMEOUT waiting for reply"), true},
+		{"no match", errCustom, false},
+		{"nil error", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, classify(tt.err))
+		})
+	}
+
+	assert.Panics(t, func() { RetryOnMessage(`(`) })
+}
+
+func TestScoredClassifier(t *testing.T) {
+	classify := ScoredClassifier(1,
+		Vote{Classify: RetryOnType[tempError](), Weight: 0.6},
+		Vote{Classify: RetryOnMessage(`timeout`), Weight: 0.5},
+		Vote{Classify: RetryOnMessage(`permission`), Weight: -2},
+	)
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"single vote below threshold", tempError{}, false},
+		{"votes reach threshold", fmt.Errorf("timeout: %w", tempError{}), true},
+		{"negative vote", fmt.Errorf("timeout: permission denied: %w", tempError{}), false},
+		{"no votes", errCustom, false},
+		{"nil", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, classify(tt.err))
+		})
+	}
+}
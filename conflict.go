@@ -0,0 +1,63 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// ErrConflict reports a lost optimistic-concurrency update, e.g. a version
+// column or an ETag that changed since the value was read. OnConflict
+// retries the errors matching it by default.
+var ErrConflict = errors.New("conflict")
+
+// ConflictOption configures OnConflict.
+type ConflictOption func(*conflictConfig)
+
+// WithConflictFunc sets the function reporting whether an error of mutate
+// is a conflict.
+func WithConflictFunc(isConflict IsRetryableFunc) ConflictOption {
+	return func(c *conflictConfig) {
+		c.isConflict = isConflict
+	}
+}
+
+type conflictConfig struct {
+	isConflict IsRetryableFunc
+}
+
+// isConflict is the default conflict classifier of OnConflict.
+func isConflict(err error) bool {
+	code, ok := statusCode(err)
+	return errors.Is(err, ErrConflict) ||
+		ok && (code == http.StatusConflict || code == http.StatusPreconditionFailed)
+}
+
+// OnConflict runs a compare-and-swap update: it reads the current value with
+// read and passes it to mutate, which writes the updated value only if it
+// did not change meanwhile, e.g. with a version column or an If-Match
+// header. When mutate reports a conflict, the value is read and mutated
+// again, with the attempts and backoff of r.
+//
+// Conflicts are errors matching ErrConflict or carrying the HTTP status
+// 409 Conflict or 412 Precondition Failed, unless set with
+// WithConflictFunc. Any other error of read or mutate stops the retries,
+// see Unretryable; r must retry conflicts, as do retriers without a
+// classifier.
+func OnConflict[T any](ctx context.Context, r Retrier, read func() (T, error), mutate func(T) error, opts ...ConflictOption) error {
+	c := conflictConfig{isConflict: isConflict}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	return doContext(ctx, r, func(context.Context, int) error {
+		v, err := read()
+		if err == nil {
+			err = mutate(v)
+			if err == nil || c.isConflict(err) {
+				return err
+			}
+		}
+		return Unretryable(err)
+	})
+}
@@ -0,0 +1,83 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOnConflict(t *testing.T) {
+	r := New(WithMaxAttempts(5), WithBackoff(FixedBackoff{}))
+
+	version, value := 0, 0
+	reads := 0
+	err := OnConflict(context.Background(), r, func() ([2]int, error) {
+		reads++
+		return [2]int{version, value}, nil
+	}, func(read [2]int) error {
+		if reads < 3 {
+			version++ // a concurrent writer updated the value since it was read
+		}
+		if read[0] != version {
+			return fmt.Errorf("version %d: %w", read[0], ErrConflict)
+		}
+		version, value = version+1, read[1]+1
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, reads)
+	assert.Equal(t, 1, value)
+}
+
+func TestOnConflict_Classifier(t *testing.T) {
+	r := New(WithMaxAttempts(3), WithBackoff(FixedBackoff{}))
+	read := func() (int, error) { return 0, nil }
+
+	tests := []struct {
+		name  string
+		err   error
+		opts  []ConflictOption
+		calls int
+	}{
+		{name: "conflict", err: ErrConflict, calls: 3},
+		{name: "precondition failed", err: &HTTPError{Code: http.StatusPreconditionFailed}, calls: 3},
+		{name: "other", err: errAlwaysFail, calls: 1},
+		{
+			name: "custom", err: errCustom, calls: 3,
+			opts: []ConflictOption{WithConflictFunc(func(err error) bool { return err == errCustom })},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			err := OnConflict(context.Background(), r, read, func(int) error {
+				calls++
+				return tt.err
+			}, tt.opts...)
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.calls, calls)
+		})
+	}
+}
+
+func TestOnConflict_ReadError(t *testing.T) {
+	r := New(WithMaxAttempts(3), WithBackoff(FixedBackoff{}), WithIsRetryableFunc(func(error) bool { return true }))
+	reads, mutated := 0, false
+	err := OnConflict(context.Background(), r, func() (int, error) {
+		reads++
+		return 0, errAlwaysFail
+	}, func(int) error {
+		mutated = true
+		return nil
+	})
+
+	assert.ErrorIs(t, err, errAlwaysFail)
+	assert.True(t, IsUnretryable(err))
+	assert.Equal(t, 1, reads, "errors other than conflicts are not retried, whatever the classifier")
+	assert.False(t, mutated)
+}
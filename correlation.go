@@ -0,0 +1,78 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"strconv"
+)
+
+// WithCorrelationID gives every Do call a correlation ID, so the log lines
+// and events of one retried operation can be joined in log search. The ID
+// is taken from the context if set with ContextWithCorrelationID, or
+// generated by gen; a nil gen generates random hex IDs. Attempts read it
+// with CorrelationID. It is logged as the "call_id" attribute, set on
+// events and audit records, and carried by the returned error, see
+// CorrelationIDFromError.
+func WithCorrelationID(gen func() string) RetryOption {
+	return func(r *retrier) {
+		if gen == nil {
+			gen = defaultCorrelationID
+		}
+		r.correlationID = gen
+	}
+}
+
+type correlationKey struct{}
+
+// ContextWithCorrelationID returns a copy of ctx carrying id, used as the
+// correlation ID of Do calls made with it.
+func ContextWithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationKey{}, id)
+}
+
+// CorrelationID returns the correlation ID carried by ctx, if any.
+func CorrelationID(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(correlationKey{}).(string)
+	return id, ok && id != ""
+}
+
+// CorrelationIDFromError returns the correlation ID of the Do call that
+// returned err, if the retrier was created with WithCorrelationID.
+func CorrelationIDFromError(err error) (string, bool) {
+	var e *correlatedError
+	if errors.As(err, &e) {
+		return e.id, true
+	}
+	return "", false
+}
+
+// correlatedError attaches a correlation ID to the error returned by Do.
+type correlatedError struct {
+	err error
+	id  string
+}
+
+func (e *correlatedError) Error() string { return e.err.Error() }
+func (e *correlatedError) Unwrap() error { return e.err }
+
+// correlate ensures ctx carries a correlation ID if they are enabled.
+func (r retrier) correlate(ctx context.Context) (context.Context, string) {
+	if r.correlationID == nil {
+		return ctx, ""
+	}
+	if id, ok := CorrelationID(ctx); ok {
+		return ctx, id
+	}
+	id := r.correlationID()
+	return ContextWithCorrelationID(ctx, id), id
+}
+
+// defaultCorrelationID returns a random 16-digit hex ID.
+func defaultCorrelationID() string {
+	id := strconv.FormatUint(rand.Uint64(), 16)
+	for len(id) < 16 {
+		id = "0" + id
+	}
+	return id
+}
@@ -0,0 +1,89 @@
+package retry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithCorrelationID(t *testing.T) {
+	var logs, audit bytes.Buffer
+	events := make(chan Event, 16)
+	r := New(
+		WithMaxAttempts(2),
+		WithBackoff(FixedBackoff{Interval: time.Millisecond}),
+		WithCorrelationID(nil),
+		WithSlog(slog.New(slog.NewJSONHandler(&logs, nil)), slog.LevelInfo),
+		WithEventSink(events),
+		WithAuditWriter(&audit),
+	)
+
+	var seen []string
+	err := r.DoContext(context.Background(), func(ctx context.Context, attempt int) error {
+		id, ok := CorrelationID(ctx)
+		require.True(t, ok)
+		seen = append(seen, id)
+		return errAlwaysFail
+	})
+
+	require.Len(t, seen, 2)
+	id := seen[0]
+	assert.Len(t, id, 16)
+	assert.Equal(t, id, seen[1])
+
+	got, ok := CorrelationIDFromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, id, got)
+	assert.True(t, isExhausted(err))
+
+	var rec map[string]any
+	require.NoError(t, json.Unmarshal(logs.Bytes(), &rec))
+	assert.Equal(t, id, rec["call_id"])
+	require.NoError(t, json.Unmarshal(audit.Bytes(), &rec))
+	assert.Equal(t, id, rec["call_id"])
+
+	for range len(events) {
+		assert.Equal(t, id, (<-events).CorrelationID)
+	}
+
+	_ = r.Do(context.Background(), func(attempt int) error { return errAlwaysFail })
+	lines := strings.Split(strings.TrimSpace(audit.String()), "\n")
+	require.Len(t, lines, 2)
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &rec))
+	assert.NotEqual(t, id, rec["call_id"], "every call gets its own ID")
+}
+
+func TestWithCorrelationID_FromContext(t *testing.T) {
+	r := New(WithMaxAttempts(1), WithCorrelationID(func() string { return "generated" }))
+
+	ctx := ContextWithCorrelationID(context.Background(), "req-7")
+	err := r.DoContext(ctx, func(ctx context.Context, attempt int) error {
+		id, _ := CorrelationID(ctx)
+		assert.Equal(t, "req-7", id)
+		return errAlwaysFail
+	})
+	id, _ := CorrelationIDFromError(err)
+	assert.Equal(t, "req-7", id)
+
+	err = r.Do(context.Background(), func(attempt int) error { return errAlwaysFail })
+	id, _ = CorrelationIDFromError(err)
+	assert.Equal(t, "generated", id)
+}
+
+func TestCorrelationID_Disabled(t *testing.T) {
+	r := New(WithMaxAttempts(1))
+	err := r.DoContext(context.Background(), func(ctx context.Context, attempt int) error {
+		_, ok := CorrelationID(ctx)
+		assert.False(t, ok)
+		return errAlwaysFail
+	})
+	_, ok := CorrelationIDFromError(err)
+	assert.False(t, ok)
+}
@@ -0,0 +1,73 @@
+package retry
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// registry holds the retriers created with WithName, by name.
+var registry sync.Map
+
+// register adds r to the registry, replacing any retrier of the same name.
+func register(r *retrier) {
+	registry.Store(r.name, r)
+}
+
+// debugInfo is the state of a retrier rendered by DebugHandler.
+type debugInfo struct {
+	Name           string `json:"name"`
+	MaxAttempts    int    `json:"max_attempts"`
+	Backoff        string `json:"backoff"`
+	AttemptTimeout string `json:"attempt_timeout,omitempty"`
+	InFlight       int64  `json:"in_flight"`
+	Sleeping       int64  `json:"sleeping"`
+	Stats          *Stats `json:"stats,omitempty"`
+}
+
+func (r *retrier) debugInfo() debugInfo {
+	info := debugInfo{
+		Name:        r.name,
+		MaxAttempts: r.maxAttempts,
+		Backoff:     strings.TrimPrefix(fmt.Sprintf("%T%+v", r.backoff, r.backoff), "retry."),
+		InFlight:    r.live.inFlight.Load(),
+		Sleeping:    r.live.sleeping.Load(),
+	}
+	if r.attemptTimeout > 0 {
+		info.AttemptTimeout = r.attemptTimeout.String()
+	}
+	if r.stats != nil {
+		stats := r.stats.snapshot()
+		info.Stats = &stats
+	}
+	return info
+}
+
+// DebugHandler returns an http.Handler rendering, as JSON, every retrier
+// created with WithName: its configuration, its counters if WithStats is
+// set, and the number of calls in progress and sleeping between attempts.
+// It is meant to be mounted under /debug/retry:
+//
+//	http.Handle("/debug/retry", retry.DebugHandler())
+//
+// A retrier created with the name of an existing one replaces it.
+func DebugHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		var infos []debugInfo
+		registry.Range(func(_, v any) bool {
+			infos = append(infos, v.(*retrier).debugInfo())
+			return true
+		})
+		slices.SortFunc(infos, func(a, b debugInfo) int {
+			return strings.Compare(a.Name, b.Name)
+		})
+
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(map[string][]debugInfo{"retriers": infos})
+	})
+}
@@ -0,0 +1,79 @@
+package retry
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func getDebug(t *testing.T) map[string]debugInfo {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	DebugHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/retry", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var body struct {
+		Retriers []debugInfo `json:"retriers"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+
+	infos := make(map[string]debugInfo)
+	for _, info := range body.Retriers {
+		infos[info.Name] = info
+	}
+	return infos
+}
+
+func TestDebugHandler(t *testing.T) {
+	r := New(
+		WithName("debug-test"),
+		WithMaxAttempts(2),
+		WithBackoff(FixedBackoff{Interval: time.Hour}),
+		WithAttemptTimeout(time.Second),
+		WithStats(),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	failed := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = r.Do(ctx, func(attempt int) error {
+			close(failed)
+			return errAlwaysFail
+		})
+	}()
+	<-failed
+
+	var info debugInfo
+	require.Eventually(t, func() bool {
+		info = getDebug(t)["debug-test"]
+		return info.Sleeping == 1
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, 2, info.MaxAttempts)
+	assert.Equal(t, "FixedBackoff{Interval:1h0m0s Jitter:0}", info.Backoff)
+	assert.Equal(t, "1s", info.AttemptTimeout)
+	assert.Equal(t, int64(1), info.InFlight)
+	require.NotNil(t, info.Stats)
+	assert.Equal(t, uint64(1), info.Stats.Attempts)
+
+	cancel()
+	<-done
+	info = getDebug(t)["debug-test"]
+	assert.Zero(t, info.InFlight)
+	assert.Zero(t, info.Sleeping)
+	assert.Equal(t, uint64(1), info.Stats.Calls)
+}
+
+func TestDebugHandler_Unnamed(t *testing.T) {
+	_ = New(WithStats())
+	assert.NotContains(t, getDebug(t), "")
+}
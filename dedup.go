@@ -0,0 +1,62 @@
+package retry
+
+import (
+	"context"
+	"sync"
+)
+
+// WithDedupKeyFunc makes concurrent Do calls with the same key share one
+// retried execution: the first call runs its function, and the calls
+// joining it while it is in flight wait for it and receive its error and
+// value without running their own. key returns the key of the call
+// carried by ctx; calls with an empty key are never deduplicated. A
+// joining call whose context is done stops waiting and returns the context
+// error, while the shared execution runs with the context of the first
+// call.
+func WithDedupKeyFunc(key func(ctx context.Context) string) RetryOption {
+	return func(r *retrier) {
+		r.dedupKey = key
+		r.dedup = &dedupGroup{calls: make(map[string]*dedupCall)}
+	}
+}
+
+// dedupGroup tracks the in-flight executions by key.
+type dedupGroup struct {
+	mu    sync.Mutex
+	calls map[string]*dedupCall
+}
+
+// dedupCall is an in-flight execution; val and err are set before done
+// is closed.
+type dedupCall struct {
+	done chan struct{}
+	val  any
+	err  error
+}
+
+// do runs fn unless an execution with key is in flight, in which case it
+// waits for the result of that execution.
+func (g *dedupGroup) do(ctx context.Context, key string, fn func() (any, error)) (any, error) {
+	g.mu.Lock()
+	if c, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		select {
+		case <-c.done:
+			return c.val, c.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	c := &dedupCall{done: make(chan struct{})}
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		close(c.done)
+	}()
+	c.val, c.err = fn()
+	return c.val, c.err
+}
@@ -0,0 +1,109 @@
+package retry
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type dedupKeyCtx struct{}
+
+// joiningContext is a context reporting on joined the first call to its
+// Done method, made by a call joining an in-flight execution as it starts
+// waiting for it.
+type joiningContext struct {
+	context.Context
+	joined chan<- struct{}
+	once   sync.Once
+}
+
+func (c *joiningContext) Done() <-chan struct{} {
+	c.once.Do(func() { c.joined <- struct{}{} })
+	return c.Context.Done()
+}
+
+func TestWithDedupKeyFunc(t *testing.T) {
+	r := New(
+		WithMaxAttempts(2),
+		WithBackoff(FixedBackoff{Interval: time.Millisecond}),
+		WithDedupKeyFunc(func(ctx context.Context) string {
+			key, _ := ctx.Value(dedupKeyCtx{}).(string)
+			return key
+		}),
+	)
+
+	var (
+		calls   atomic.Int32
+		started = make(chan struct{})
+		release = make(chan struct{})
+	)
+	leader := func(int) error {
+		if calls.Add(1) == 1 {
+			close(started)
+		}
+		<-release
+		return errAlwaysFail
+	}
+	follower := func(int) error {
+		calls.Add(1)
+		return nil
+	}
+
+	ctx := context.WithValue(context.Background(), dedupKeyCtx{}, "user:1")
+	errs := make([]error, 3)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		errs[0] = r.Do(ctx, leader)
+	}()
+	<-started
+	joined := make(chan struct{}, len(errs))
+	for i := 1; i < len(errs); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = r.Do(&joiningContext{Context: ctx, joined: joined}, follower)
+		}()
+	}
+	for i := 1; i < len(errs); i++ {
+		<-joined
+	}
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(2), calls.Load(), "only the leader runs its attempts")
+	for _, err := range errs {
+		assert.ErrorIs(t, err, errAlwaysFail)
+	}
+
+	t.Run("empty key", func(t *testing.T) {
+		calls.Store(0)
+		assert.NoError(t, r.Do(context.Background(), follower))
+		assert.Equal(t, int32(1), calls.Load())
+	})
+}
+
+func TestDedupGroup_WaiterContext(t *testing.T) {
+	g := &dedupGroup{calls: make(map[string]*dedupCall)}
+	release := make(chan struct{})
+	started := make(chan struct{})
+	go func() {
+		_, _ = g.do(context.Background(), "k", func() (any, error) {
+			close(started)
+			<-release
+			return nil, nil
+		})
+	}()
+	defer close(release)
+	<-started
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := g.do(ctx, "k", func() (any, error) { return nil, nil })
+	assert.ErrorIs(t, err, context.Canceled)
+}
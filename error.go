@@ -1,10 +1,43 @@
 package retry
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"reflect"
+	"strings"
+	"time"
 )
 
+// Stable codes identifying the package's errors, suitable for alerting
+// rules and clients that should not depend on error messages.
+const (
+	CodeExhausted      = "retry.exhausted"
+	CodeUnretryable    = "retry.unretryable"
+	CodeBudgetExceeded = "retry.budget_exceeded"
+)
+
+// ErrorCode returns the stable code of the first package error in err's
+// chain, or an empty string if there is none.
+func ErrorCode(err error) string {
+	var c codedError
+	if errors.As(err, &c) {
+		return c.Code()
+	}
+	return ""
+}
+
+// codedError is implemented by the package's error types.
+type codedError interface {
+	error
+	Code() string
+	retryError()
+}
+
+// ErrUnretryable is matched by every UnretryableError, so callers can use
+// errors.Is(err, retry.ErrUnretryable) instead of errors.As.
+var ErrUnretryable = errors.New("unretryable error")
+
 // IsUnretryable reports whether the error is marked as unretryable.
 func IsUnretryable(err error) bool {
 	var e *UnretryableError
@@ -21,6 +54,13 @@ type UnretryableError struct {
 	err error
 }
 
+// Unretryable marks err as non-retryable: an attempt returning it, or an
+// error wrapping it, stops the retries at once whatever the classifier of
+// the retrier, and Do returns it. Unretryable returns nil if err is nil.
+func Unretryable(err error) error {
+	return newUnretryableError(err)
+}
+
 func newUnretryableError(err error) error {
 	if err == nil {
 		return nil
@@ -30,3 +70,160 @@ func newUnretryableError(err error) error {
 
 func (e *UnretryableError) Error() string { return fmt.Sprintf("unretryable error: %v", e.err) }
 func (e *UnretryableError) Unwrap() error { return e.err }
+
+// Code returns CodeUnretryable.
+func (e *UnretryableError) Code() string { return CodeUnretryable }
+func (e *UnretryableError) retryError()  {}
+
+// Is reports whether target is ErrUnretryable.
+func (e *UnretryableError) Is(target error) bool { return target == ErrUnretryable }
+
+// MarshalJSON encodes the error with its cause chain.
+func (e *UnretryableError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Code  string     `json:"code"`
+		Error string     `json:"error"`
+		Cause *causeJSON `json:"cause,omitempty"`
+	}{
+		Code:  e.Code(),
+		Error: e.Error(),
+		Cause: newCauseJSON(e.err),
+	})
+}
+
+// ErrBudgetExceeded is matched by every BudgetExceededError.
+var ErrBudgetExceeded = errors.New("retry budget exceeded")
+
+// BudgetExceededError is returned when a failed attempt was not retried
+// because the retry budget (see WithBudget) was exhausted. The error of the
+// attempt can be accessed via errors.Unwrap or errors.As.
+type BudgetExceededError struct {
+	err error
+}
+
+func (e *BudgetExceededError) Error() string { return fmt.Sprintf("retry budget exceeded: %v", e.err) }
+func (e *BudgetExceededError) Unwrap() error { return e.err }
+
+// Code returns CodeBudgetExceeded.
+func (e *BudgetExceededError) Code() string { return CodeBudgetExceeded }
+func (e *BudgetExceededError) retryError()  {}
+
+// Is reports whether target is ErrBudgetExceeded.
+func (e *BudgetExceededError) Is(target error) bool { return target == ErrBudgetExceeded }
+
+// MarshalJSON encodes the error with its cause chain.
+func (e *BudgetExceededError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Code  string     `json:"code"`
+		Error string     `json:"error"`
+		Cause *causeJSON `json:"cause,omitempty"`
+	}{
+		Code:  e.Code(),
+		Error: e.Error(),
+		Cause: newCauseJSON(e.err),
+	})
+}
+
+// MaxAttemptsError is returned when every attempt failed.
+// The error of the last attempt (or the aggregated errors, see
+// WithErrorAggregation) can be accessed via errors.Unwrap or errors.As.
+type MaxAttemptsError struct {
+	// Attempts is the number of attempts made.
+	Attempts int
+	// Elapsed is the time spent from the first attempt until giving up.
+	Elapsed time.Duration
+
+	err error
+}
+
+func (e *MaxAttemptsError) Error() string { return fmt.Sprintf("all attempts failed: %v", e.err) }
+func (e *MaxAttemptsError) Unwrap() error { return e.err }
+
+// Code returns CodeExhausted.
+func (e *MaxAttemptsError) Code() string { return CodeExhausted }
+func (e *MaxAttemptsError) retryError()  {}
+
+// MarshalJSON encodes the error with its attempt count, elapsed time in
+// milliseconds and cause chain.
+func (e *MaxAttemptsError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Code      string     `json:"code"`
+		Error     string     `json:"error"`
+		Attempts  int        `json:"attempts"`
+		ElapsedMS float64    `json:"elapsed_ms"`
+		Cause     *causeJSON `json:"cause,omitempty"`
+	}{
+		Code:      e.Code(),
+		Error:     e.Error(),
+		Attempts:  e.Attempts,
+		ElapsedMS: float64(e.Elapsed) / float64(time.Millisecond),
+		Cause:     newCauseJSON(e.err),
+	})
+}
+
+// causeJSON is the JSON form of an error tree.
+type causeJSON struct {
+	Type    string      `json:"type"`
+	Message string      `json:"message"`
+	Cause   *causeJSON  `json:"cause,omitempty"`
+	Causes  []causeJSON `json:"causes,omitempty"`
+}
+
+func newCauseJSON(err error) *causeJSON {
+	if err == nil {
+		return nil
+	}
+
+	c := &causeJSON{
+		Type:    reflect.TypeOf(err).String(),
+		Message: err.Error(),
+	}
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		c.Cause = newCauseJSON(u.Unwrap())
+	case interface{ Unwrap() []error }:
+		for _, e := range u.Unwrap() {
+			if e != nil {
+				c.Causes = append(c.Causes, *newCauseJSON(e))
+			}
+		}
+	}
+	return c
+}
+
+// aggregateError holds the errors of every failed attempt.
+type aggregateError struct {
+	errs []error
+}
+
+func newAggregateError(errs []error) error {
+	if len(errs) == 1 {
+		return errs[0]
+	}
+	return &aggregateError{errs: errs}
+}
+
+// Error joins the attempt errors with newlines, collapsing runs of
+// consecutive errors with the same message into "message (xN)".
+func (e *aggregateError) Error() string {
+	var b strings.Builder
+	for i := 0; i < len(e.errs); {
+		msg := e.errs[i].Error()
+		n := 1
+		for i+n < len(e.errs) && e.errs[i+n].Error() == msg {
+			n++
+		}
+
+		if b.Len() > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(msg)
+		if n > 1 {
+			fmt.Fprintf(&b, " (x%d)", n)
+		}
+		i += n
+	}
+	return b.String()
+}
+
+func (e *aggregateError) Unwrap() []error { return e.errs }
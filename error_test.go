@@ -0,0 +1,152 @@
+package retry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregateError(t *testing.T) {
+	refused := errors.New("connection refused")
+
+	tests := []struct {
+		name string
+		errs []error
+		want string
+	}{
+		{"single", []error{refused}, "connection refused"},
+		{"distinct", []error{refused, errCustom}, "connection refused\ncustom error"},
+		{"collapsed run", []error{refused, refused, refused}, "connection refused (x3)"},
+		{
+			"runs are consecutive only",
+			[]error{refused, refused, errCustom, refused, errors.New("connection refused")},
+			"connection refused (x2)\ncustom error\nconnection refused (x2)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newAggregateError(tt.errs)
+			assert.Equal(t, tt.want, err.Error())
+			for _, e := range tt.errs {
+				assert.ErrorIs(t, err, e)
+			}
+		})
+	}
+}
+
+func TestUnretryableError(t *testing.T) {
+	err := fmt.Errorf("do: %w", newUnretryableError(errCustom))
+
+	assert.ErrorIs(t, err, ErrUnretryable)
+	assert.ErrorIs(t, err, errCustom)
+	assert.True(t, IsUnretryable(err))
+	assert.NotErrorIs(t, errCustom, ErrUnretryable)
+	assert.Nil(t, newUnretryableError(nil))
+}
+
+func TestUnretryable(t *testing.T) {
+	calls := 0
+	r := New(WithMaxAttempts(3), WithBackoff(FixedBackoff{}), WithIsRetryableFunc(func(error) bool { return true }))
+	err := r.Do(context.Background(), func(attempt int) error {
+		calls++
+		return fmt.Errorf("write: %w", Unretryable(errCustom))
+	})
+	assert.Equal(t, 1, calls, "the classifier cannot retry an unretryable error")
+	assert.ErrorIs(t, err, errCustom)
+	assert.EqualError(t, err, "write: unretryable error: custom error")
+	assert.Nil(t, Unretryable(nil))
+}
+
+func TestErrorsMarshalJSON(t *testing.T) {
+	t.Run("max attempts", func(t *testing.T) {
+		err := &MaxAttemptsError{
+			Attempts: 3,
+			Elapsed:  1500 * time.Microsecond,
+			err:      fmt.Errorf("dial: %w", errAlwaysFail),
+		}
+
+		got, jerr := json.Marshal(err)
+		require.NoError(t, jerr)
+		assert.JSONEq(t, `{
+			"code": "retry.exhausted",
+			"error": "all attempts failed: dial: always fail",
+			"attempts": 3,
+			"elapsed_ms": 1.5,
+			"cause": {
+				"type": "*fmt.wrapError",
+				"message": "dial: always fail",
+				"cause": {"type": "*errors.errorString", "message": "always fail"}
+			}
+		}`, string(got))
+	})
+
+	t.Run("unretryable", func(t *testing.T) {
+		got, jerr := json.Marshal(newUnretryableError(errCustom))
+		require.NoError(t, jerr)
+		assert.JSONEq(t, `{
+			"code": "retry.unretryable",
+			"error": "unretryable error: custom error",
+			"cause": {"type": "*errors.errorString", "message": "custom error"}
+		}`, string(got))
+	})
+
+	t.Run("aggregated causes", func(t *testing.T) {
+		err := &MaxAttemptsError{Attempts: 2, err: newAggregateError([]error{errAlwaysFail, errCustom})}
+
+		got, jerr := json.Marshal(err)
+		require.NoError(t, jerr)
+		assert.JSONEq(t, `{
+			"code": "retry.exhausted",
+			"error": "all attempts failed: always fail\ncustom error",
+			"attempts": 2,
+			"elapsed_ms": 0,
+			"cause": {
+				"type": "*retry.aggregateError",
+				"message": "always fail\ncustom error",
+				"causes": [
+					{"type": "*errors.errorString", "message": "always fail"},
+					{"type": "*errors.errorString", "message": "custom error"}
+				]
+			}
+		}`, string(got))
+	})
+}
+
+func TestRetrier_DoMaxAttemptsError(t *testing.T) {
+	err := New(
+		WithMaxAttempts(2),
+		WithBackoff(FixedBackoff{Interval: time.Millisecond}),
+	).Do(context.Background(), func(attempt int) error { return errAlwaysFail })
+
+	var mae *MaxAttemptsError
+	require.ErrorAs(t, err, &mae)
+	assert.Equal(t, 2, mae.Attempts)
+	assert.Positive(t, mae.Elapsed)
+	assert.ErrorIs(t, err, errAlwaysFail)
+}
+
+func TestErrorCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"exhausted", fmt.Errorf("sync: %w", &MaxAttemptsError{err: errAlwaysFail}), CodeExhausted},
+		{"unretryable", newUnretryableError(errCustom), CodeUnretryable},
+		{"plain", errCustom, ""},
+		{"nil", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ErrorCode(tt.err))
+		})
+	}
+}
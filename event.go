@@ -0,0 +1,106 @@
+package retry
+
+import (
+	"context"
+	"time"
+)
+
+// EventKind identifies the kind of an Event.
+type EventKind int
+
+const (
+	// AttemptStarted is emitted before every attempt.
+	AttemptStarted EventKind = iota
+	// AttemptFailed is emitted after every failed attempt.
+	AttemptFailed
+	// Sleeping is emitted before waiting for the next attempt.
+	Sleeping
+	// GaveUp is emitted when a call returns an error.
+	GaveUp
+	// Succeeded is emitted when a call succeeds.
+	Succeeded
+)
+
+// String returns the name of the event kind.
+func (k EventKind) String() string {
+	switch k {
+	case AttemptStarted:
+		return "AttemptStarted"
+	case AttemptFailed:
+		return "AttemptFailed"
+	case Sleeping:
+		return "Sleeping"
+	case GaveUp:
+		return "GaveUp"
+	case Succeeded:
+		return "Succeeded"
+	}
+	return "EventKind(unknown)"
+}
+
+// Event describes a step of a Do call.
+type Event struct {
+	Kind EventKind
+	Time time.Time
+	// Retrier is the name set by WithName.
+	Retrier string
+	// CorrelationID is the ID of the call, see WithCorrelationID.
+	CorrelationID string
+	// Attempt is the zero-based attempt number; for GaveUp and Succeeded
+	// it is the number of attempts made.
+	Attempt int
+	// Err is the attempt error for AttemptFailed and Sleeping, and the
+	// error returned to the caller for GaveUp.
+	Err error
+	// Delay is the wait before the next attempt, set for Sleeping.
+	Delay time.Duration
+	// Duration is the duration of the attempt, set for AttemptFailed.
+	Duration time.Duration
+}
+
+// WithEventSink emits an Event for every step of every Do call to ch, so
+// external systems can observe retries. Events are sent without blocking
+// and dropped when ch is full, so a slow consumer never stalls retries.
+func WithEventSink(ch chan<- Event) RetryOption {
+	return func(r *retrier) {
+		r.extraHooks = append(r.extraHooks, sampledHook{h: eventHook{ch: ch, r: r}})
+	}
+}
+
+// eventHook emits events to a channel.
+type eventHook struct {
+	ch chan<- Event
+	r  *retrier
+}
+
+func (h eventHook) emit(ctx context.Context, e Event) {
+	e.Time = time.Now()
+	e.Retrier = h.r.name
+	e.CorrelationID, _ = CorrelationID(ctx)
+	select {
+	case h.ch <- e:
+	default:
+	}
+}
+
+func (h eventHook) attemptStarted(ctx context.Context, attempt int) {
+	h.emit(ctx, Event{Kind: AttemptStarted, Attempt: attempt})
+}
+
+func (h eventHook) attemptFinished(ctx context.Context, attempt int, err error, d time.Duration) {
+	if err != nil {
+		h.emit(ctx, Event{Kind: AttemptFailed, Attempt: attempt, Err: err, Duration: d})
+	}
+}
+
+func (h eventHook) retrying(ctx context.Context, attempt int, err error, delay time.Duration) {
+	h.emit(ctx, Event{Kind: Sleeping, Attempt: attempt, Err: err, Delay: delay})
+}
+
+func (h eventHook) finished(ctx context.Context, attempts int, err error) {
+	if err != nil {
+		h.emit(ctx, Event{Kind: GaveUp, Attempt: attempts, Err: err})
+		return
+	}
+	h.emit(ctx, Event{Kind: Succeeded, Attempt: attempts})
+}
@@ -0,0 +1,69 @@
+package retry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithEventSink(t *testing.T) {
+	ch := make(chan Event, 32)
+	r := New(
+		WithMaxAttempts(3),
+		WithBackoff(FixedBackoff{Interval: time.Millisecond}),
+		WithEventSink(ch),
+	)
+
+	_ = r.Do(context.Background(), func(attempt int) error {
+		if attempt == 0 {
+			return errAlwaysFail
+		}
+		return nil
+	})
+	_ = r.Do(context.Background(), func(attempt int) error { return errCustom })
+	close(ch)
+
+	var kinds []EventKind
+	for e := range ch {
+		assert.False(t, e.Time.IsZero())
+		kinds = append(kinds, e.Kind)
+		if e.Kind == Sleeping {
+			assert.Equal(t, time.Millisecond, e.Delay)
+		}
+	}
+
+	assert.Equal(t, []EventKind{
+		AttemptStarted, AttemptFailed, Sleeping, AttemptStarted, Succeeded,
+		AttemptStarted, AttemptFailed, Sleeping, AttemptStarted, AttemptFailed, Sleeping,
+		AttemptStarted, AttemptFailed, GaveUp,
+	}, kinds)
+}
+
+func TestWithEventSink_DropsWhenFull(t *testing.T) {
+	ch := make(chan Event, 1)
+	r := New(
+		WithMaxAttempts(3),
+		WithBackoff(FixedBackoff{Interval: time.Millisecond}),
+		WithEventSink(ch),
+	)
+
+	done := make(chan struct{})
+	go func() {
+		_ = r.Do(context.Background(), func(attempt int) error { return errAlwaysFail })
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Do blocked on a full event sink")
+	}
+	assert.Equal(t, AttemptStarted, (<-ch).Kind)
+}
+
+func TestEventKindString(t *testing.T) {
+	assert.Equal(t, "GaveUp", GaveUp.String())
+	assert.Equal(t, "EventKind(unknown)", EventKind(99).String())
+}
@@ -0,0 +1,104 @@
+package retry
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Explain returns a human-readable summary of r's policy for startup logs
+// and runbooks, e.g. "up to 5 attempts, exponential 100ms x2 capped 10s,
+// ±20% jitter, stop on: not found".
+func Explain(r Retrier) string {
+	switch r := r.(type) {
+	case *retrier:
+		return r.explain()
+	case *noRetrier:
+		return "single attempt, no retries"
+	}
+	return fmt.Sprintf("%T", r)
+}
+
+func (r *retrier) explain() string {
+	var parts []string
+	if r.maxAttempts == 0 {
+		parts = append(parts, "unlimited attempts")
+	} else {
+		parts = append(parts, "up to "+strconv.Itoa(r.maxAttempts)+" attempts")
+	}
+	parts = append(parts, explainBackoff(r.backoff))
+	if r.attemptTimeout > 0 {
+		parts = append(parts, "attempt timeout "+r.attemptTimeout.String())
+	}
+	if r.tierFunc != nil {
+		parts = append(parts, "tiered retry check")
+	} else {
+		parts = append(parts, r.retryableDesc)
+	}
+	if r.aggregateErrors {
+		parts = append(parts, "aggregated errors")
+	}
+
+	s := strings.Join(parts, ", ")
+	if r.name != "" {
+		s = r.name + ": " + s
+	}
+	return s
+}
+
+// explainBackoff describes the package's backoff strategies, and others
+// through their String method or type.
+func explainBackoff(b Backoff) string {
+	switch b := b.(type) {
+	case FixedBackoff:
+		return withJitter("fixed "+b.Interval.String(), b.Jitter)
+	case LinearBackoff:
+		return withJitter(capped("linear "+b.Base.String()+" +"+b.Step.String(), b.Max), b.Jitter)
+	case ExponentialBackoff:
+		return withJitter(capped("exponential "+b.Base.String()+" x"+formatFactor(b.Factor), b.Max), b.Jitter)
+	case IntervalWindowBackoff:
+		s := capped("exponential "+b.Interval.String()+" x"+formatFactor(max(b.Multiplier, 1)), b.Max)
+		if b.RandomizationFactor > 0 && b.RandomizationFactor <= 1 {
+			s += ", randomized " + formatPercent(b.RandomizationFactor)
+		}
+		return s
+	case replayBackoff:
+		return "replayed delays"
+	case fmt.Stringer:
+		return b.String()
+	}
+	return fmt.Sprintf("%T backoff", b)
+}
+
+func capped(s string, limit time.Duration) string {
+	if limit > 0 {
+		return s + " capped " + limit.String()
+	}
+	return s
+}
+
+// withJitter appends jitter within the range accepted by addJitter.
+func withJitter(s string, jitter float64) string {
+	if jitter > 0 && jitter < 1 {
+		return s + ", " + formatPercent(jitter) + " jitter"
+	}
+	return s
+}
+
+func formatFactor(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+func formatPercent(f float64) string {
+	return "±" + strconv.FormatFloat(f*100, 'g', 3, 64) + "%"
+}
+
+// errorList joins the messages of errs.
+func errorList(errs []error) string {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, ", ")
+}
@@ -0,0 +1,78 @@
+package retry
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type namedBackoff struct{ FixedBackoff }
+
+func (namedBackoff) String() string { return "adaptive" }
+
+func TestExplain(t *testing.T) {
+	errNotFound := errors.New("not found")
+	errConflict := errors.New("conflict")
+
+	tests := []struct {
+		name string
+		r    Retrier
+		want string
+	}{
+		{
+			name: "defaults",
+			r:    New(),
+			want: "up to 3 attempts, linear 1s +1s capped 10s, ±10% jitter, retry on any error",
+		},
+		{
+			name: "exponential",
+			r: New(
+				WithName("payments"),
+				WithMaxAttempts(5),
+				WithBackoff(ExponentialBackoff{Base: 100 * time.Millisecond, Factor: 2, Max: 10 * time.Second, Jitter: 0.2}),
+				WithAttemptTimeout(2*time.Second),
+				WithUnretryableErrors(errNotFound, errConflict),
+			),
+			want: "payments: up to 5 attempts, exponential 100ms x2 capped 10s, ±20% jitter, attempt timeout 2s, stop on: not found, conflict",
+		},
+		{
+			name: "interval window",
+			r: New(
+				WithMaxAttempts(0),
+				WithBackoff(IntervalWindowBackoff{Interval: time.Second, Multiplier: 1.5, RandomizationFactor: 0.5}),
+				WithRetryableErrors(errConflict),
+				WithErrorAggregation(),
+			),
+			want: "unlimited attempts, exponential 1s x1.5, randomized ±50%, retry on: conflict, aggregated errors",
+		},
+		{
+			name: "custom",
+			r: New(
+				WithBackoff(namedBackoff{}),
+				WithIsRetryableFunc(func(error) bool { return true }),
+			),
+			want: "up to 3 attempts, adaptive, custom retry check",
+		},
+		{
+			name: "tiers",
+			r: New(
+				WithBackoff(FixedBackoff{Interval: time.Second}),
+				WithTierFunc(func(error) Tier { return RetryFast }),
+			),
+			want: "up to 3 attempts, fixed 1s, tiered retry check",
+		},
+		{
+			name: "no retry",
+			r:    NoRetry(),
+			want: "single attempt, no retries",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Explain(tt.r))
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package retry
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// expvarMu serializes lookups and publication of expvar maps.
+var expvarMu sync.Mutex
+
+// WithExpvar publishes the retrier's counters under name in expvar, so
+// they appear on /debug/vars:
+//   - attempts: attempts made
+//   - retries: retries scheduled after failed attempts
+//   - exhaustions: calls that ran out of attempts
+//   - last_error: message of the most recent failed attempt
+//
+// Retriers created with the same name share the published map.
+// It panics if name is already published as something other than an
+// *expvar.Map.
+func WithExpvar(name string) RetryOption {
+	m := expvarMap(name)
+	h := &expvarHook{
+		attempts:    expvarInt(m, "attempts"),
+		retries:     expvarInt(m, "retries"),
+		exhaustions: expvarInt(m, "exhaustions"),
+		lastError:   expvarString(m, "last_error"),
+	}
+
+	return func(r *retrier) {
+		r.extraHooks = append(r.extraHooks, h)
+	}
+}
+
+func expvarMap(name string) *expvar.Map {
+	expvarMu.Lock()
+	defer expvarMu.Unlock()
+
+	if v := expvar.Get(name); v != nil {
+		m, ok := v.(*expvar.Map)
+		if !ok {
+			panic(fmt.Sprintf("retry: expvar %q is a %T, not an *expvar.Map", name, v))
+		}
+		return m
+	}
+	return expvar.NewMap(name)
+}
+
+func expvarInt(m *expvar.Map, key string) *expvar.Int {
+	expvarMu.Lock()
+	defer expvarMu.Unlock()
+
+	if v, ok := m.Get(key).(*expvar.Int); ok {
+		return v
+	}
+	v := new(expvar.Int)
+	m.Set(key, v)
+	return v
+}
+
+func expvarString(m *expvar.Map, key string) *expvar.String {
+	expvarMu.Lock()
+	defer expvarMu.Unlock()
+
+	if v, ok := m.Get(key).(*expvar.String); ok {
+		return v
+	}
+	v := new(expvar.String)
+	m.Set(key, v)
+	return v
+}
+
+// expvarHook records counters into expvar variables.
+type expvarHook struct {
+	nopHook
+	attempts    *expvar.Int
+	retries     *expvar.Int
+	exhaustions *expvar.Int
+	lastError   *expvar.String
+}
+
+func (h *expvarHook) attemptStarted(context.Context, int) { h.attempts.Add(1) }
+
+func (h *expvarHook) attemptFinished(_ context.Context, _ int, err error, _ time.Duration) {
+	if err != nil {
+		h.lastError.Set(err.Error())
+	}
+}
+
+func (h *expvarHook) retrying(context.Context, int, error, time.Duration) { h.retries.Add(1) }
+
+func (h *expvarHook) finished(_ context.Context, _ int, err error) {
+	if isExhausted(err) {
+		h.exhaustions.Add(1)
+	}
+}
@@ -0,0 +1,34 @@
+package retry
+
+import (
+	"context"
+	"expvar"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithExpvar(t *testing.T) {
+	opts := []RetryOption{
+		WithMaxAttempts(2),
+		WithBackoff(FixedBackoff{Interval: time.Millisecond}),
+		WithExpvar("retry_test_expvar"),
+	}
+
+	_ = New(opts...).Do(context.Background(), func(attempt int) error { return errAlwaysFail })
+	_ = New(opts...).Do(context.Background(), func(attempt int) error { return nil })
+
+	m, ok := expvar.Get("retry_test_expvar").(*expvar.Map)
+	require.True(t, ok)
+	assert.Equal(t, "3", m.Get("attempts").String())
+	assert.Equal(t, "1", m.Get("retries").String())
+	assert.Equal(t, "1", m.Get("exhaustions").String())
+	assert.Equal(t, `"always fail"`, m.Get("last_error").String())
+}
+
+func TestWithExpvar_Conflict(t *testing.T) {
+	expvar.NewInt("retry_test_expvar_int")
+	assert.Panics(t, func() { WithExpvar("retry_test_expvar_int") })
+}
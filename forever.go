@@ -0,0 +1,114 @@
+package retry
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ForeverOption configures Forever.
+type ForeverOption func(*forever)
+
+// WithResetAfter sets how long a connection must last for the backoff to be
+// reset when it ends. The default is one minute.
+func WithResetAfter(d time.Duration) ForeverOption {
+	return func(f *forever) {
+		f.resetAfter = d
+	}
+}
+
+// WithOnConnect calls fn whenever connect reports an established
+// connection with Connected.
+func WithOnConnect(fn func(ctx context.Context)) ForeverOption {
+	return func(f *forever) {
+		f.onConnect = fn
+	}
+}
+
+// WithOnDisconnect calls fn whenever an established connection ends, with
+// the error connect returned and how long the connection lasted.
+func WithOnDisconnect(fn func(err error, uptime time.Duration)) ForeverOption {
+	return func(f *forever) {
+		f.onDisconnect = fn
+	}
+}
+
+type forever struct {
+	resetAfter   time.Duration
+	onConnect    func(context.Context)
+	onDisconnect func(error, time.Duration)
+}
+
+type connectionKey struct{}
+
+// connection is the state of a call to connect.
+type connection struct {
+	once        sync.Once
+	connectedAt time.Time
+}
+
+// Connected reports that the connection of the connect function run by
+// Forever with ctx is established. It may be called once per call to
+// connect; later calls do nothing.
+func Connected(ctx context.Context) {
+	c, ok := ctx.Value(connectionKey{}).(*connection)
+	if !ok {
+		return
+	}
+	c.once.Do(func() {
+		c.connectedAt = time.Now()
+		if f, ok := ctx.Value(foreverKey{}).(*forever); ok && f.onConnect != nil {
+			f.onConnect(ctx)
+		}
+	})
+}
+
+type foreverKey struct{}
+
+// Forever maintains a connection until ctx is done, e.g. to a message
+// broker or a streaming API. connect establishes the connection, reports
+// it with Connected and serves it until it breaks. Whenever connect
+// returns or panics, Forever calls it again after the next delay of b, or
+// of the default backoff if b is nil. Once a connection lasted for the
+// reset threshold, see WithResetAfter, the next delay starts over from the
+// first one. Forever only returns ctx.Err(), once ctx is done.
+func Forever(ctx context.Context, b Backoff, connect func(ctx context.Context) error, opts ...ForeverOption) error {
+	f := &forever{resetAfter: time.Minute}
+	for _, opt := range opts {
+		opt(f)
+	}
+	if b == nil {
+		b = defaultBackoff()
+	}
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	<-timer.C
+
+	for attempt := 0; ; attempt++ {
+		c := &connection{}
+		cctx := context.WithValue(context.WithValue(ctx, foreverKey{}, f), connectionKey{}, c)
+		err := recovered(func() error { return connect(cctx) })
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+
+		c.once.Do(func() {}) // ignore Connected calls from now
+		if !c.connectedAt.IsZero() {
+			uptime := time.Since(c.connectedAt)
+			if f.onDisconnect != nil {
+				f.onDisconnect(err, uptime)
+			}
+			if uptime >= f.resetAfter {
+				attempt = 0
+			}
+		}
+
+		timer.Reset(b.Next(attempt))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type attemptsBackoff struct {
+	attempts []int
+}
+
+func (b *attemptsBackoff) Next(attempt int) time.Duration {
+	b.attempts = append(b.attempts, attempt)
+	return time.Millisecond
+}
+
+func TestForever(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	b := &attemptsBackoff{}
+	connects := 0
+	var disconnects []error
+	errDropped := errors.New("dropped")
+
+	runs := 0
+	err := Forever(ctx, b, func(ctx context.Context) error {
+		runs++
+		switch runs {
+		case 1:
+			return errAlwaysFail // never connected
+		case 2:
+			Connected(ctx)
+			return errDropped // connected briefly
+		case 3:
+			panic("boom")
+		case 4:
+			Connected(ctx)
+			time.Sleep(20 * time.Millisecond) // long-lived
+			return errDropped
+		case 5:
+			return errAlwaysFail
+		}
+		cancel()
+		return nil
+	},
+		WithResetAfter(10*time.Millisecond),
+		WithOnConnect(func(context.Context) { connects++ }),
+		WithOnDisconnect(func(err error, uptime time.Duration) {
+			disconnects = append(disconnects, err)
+		}),
+	)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 6, runs)
+	assert.Equal(t, 2, connects)
+	assert.Equal(t, []error{errDropped, errDropped}, disconnects)
+	assert.Equal(t, []int{0, 1, 2, 0, 1}, b.attempts, "the backoff is reset after a long-lived connection")
+}
+
+func TestConnected_OutsideForever(t *testing.T) {
+	assert.NotPanics(t, func() { Connected(context.Background()) })
+}
@@ -0,0 +1,74 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+)
+
+// ShedPolicy is what a call does when the global retry limit set with
+// SetGlobalRetryLimit is reached.
+type ShedPolicy int
+
+const (
+	// ShedStop stops retrying: the call returns a BudgetExceededError
+	// wrapping the last attempt error.
+	ShedStop ShedPolicy = iota
+	// ShedWait waits for a free slot until the call's context is done.
+	ShedWait
+)
+
+// globalRetries is the process-wide retry limiter, nil when unlimited.
+var globalRetries atomic.Pointer[globalLimiter]
+
+// errRetryShed is returned by acquireRetrySlot when the call is shed.
+var errRetryShed = errors.New("global retry limit reached")
+
+// SetGlobalRetryLimit bounds to n the number of Do calls, across every
+// retrier of the process, that are retrying at once. A call takes a slot
+// before its first retry and holds it until it returns, so calls that
+// succeed at the first attempt are never limited. When no slot is free,
+// policy decides whether the call stops or waits. This protects the
+// process during a full downstream outage, when every in-flight call would
+// otherwise sleep and retry. A value of n <= 0 removes the limit, which is
+// the default.
+func SetGlobalRetryLimit(n int, policy ShedPolicy) {
+	if n <= 0 {
+		globalRetries.Store(nil)
+		return
+	}
+	globalRetries.Store(&globalLimiter{slots: make(chan struct{}, n), policy: policy})
+}
+
+// globalLimiter is a semaphore applying a ShedPolicy when full.
+type globalLimiter struct {
+	slots  chan struct{}
+	policy ShedPolicy
+}
+
+// acquireRetrySlot takes a slot of the global retry limiter, if one is
+// set, and returns the function releasing it.
+func acquireRetrySlot(ctx context.Context) (func(), error) {
+	l := globalRetries.Load()
+	if l == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+		return l.release, nil
+	default:
+	}
+	if l.policy == ShedStop {
+		return nil, errRetryShed
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+		return l.release, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (l *globalLimiter) release() { <-l.slots }
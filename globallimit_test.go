@@ -0,0 +1,83 @@
+package retry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// holdRetrySlot starts a call that takes the only global slot and keeps
+// it until the returned function is called.
+func holdRetrySlot(t *testing.T) func() {
+	t.Helper()
+
+	retrying := make(chan struct{})
+	done := make(chan struct{})
+	r := New(
+		WithMaxAttempts(2),
+		WithBackoff(FixedBackoff{Interval: time.Hour}),
+		WithOnRetry(func(context.Context, int, error, time.Duration) { close(retrying) }),
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		defer close(done)
+		_ = r.Do(ctx, func(int) error { return errAlwaysFail })
+	}()
+	<-retrying
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+func TestSetGlobalRetryLimit_Stop(t *testing.T) {
+	SetGlobalRetryLimit(1, ShedStop)
+	t.Cleanup(func() { SetGlobalRetryLimit(0, ShedStop) })
+	stop := holdRetrySlot(t)
+
+	calls := 0
+	r := New(WithMaxAttempts(3), WithBackoff(FixedBackoff{}))
+	err := r.Do(context.Background(), func(int) error {
+		calls++
+		return errAlwaysFail
+	})
+	assert.ErrorIs(t, err, ErrBudgetExceeded)
+	assert.ErrorIs(t, err, errAlwaysFail)
+	assert.Equal(t, 1, calls)
+
+	stop()
+	calls = 0
+	err = r.Do(context.Background(), func(int) error {
+		calls++
+		return errAlwaysFail
+	})
+	var mae *MaxAttemptsError
+	assert.ErrorAs(t, err, &mae)
+	assert.Equal(t, 3, calls, "the slot is released when the call returns")
+}
+
+func TestSetGlobalRetryLimit_Wait(t *testing.T) {
+	SetGlobalRetryLimit(1, ShedWait)
+	t.Cleanup(func() { SetGlobalRetryLimit(0, ShedStop) })
+	stop := holdRetrySlot(t)
+	defer stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	r := New(WithMaxAttempts(3), WithBackoff(FixedBackoff{}))
+	err := r.Do(ctx, func(int) error { return errAlwaysFail })
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestSetGlobalRetryLimit_FirstAttemptUnlimited(t *testing.T) {
+	SetGlobalRetryLimit(1, ShedStop)
+	t.Cleanup(func() { SetGlobalRetryLimit(0, ShedStop) })
+	stop := holdRetrySlot(t)
+	defer stop()
+
+	err := New().Do(context.Background(), func(int) error { return nil })
+	assert.NoError(t, err)
+}
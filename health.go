@@ -0,0 +1,50 @@
+package retry
+
+import (
+	"context"
+	"time"
+)
+
+// healthCheckAfter is the number of failed attempts of a call after which
+// the health check set with WithHealthCheck gates its retries.
+const healthCheckAfter = 2
+
+// defaultProbeInterval replaces non-positive probe intervals passed to
+// WithHealthCheck.
+const defaultProbeInterval = time.Second
+
+// WithHealthCheck gates retries on a cheap health probe of the dependency,
+// so attempts are not burnt against a known-dead one. Once two attempts of
+// a call have failed, check is called before every further attempt; while
+// it returns an error, Do calls it again every probeInterval, until it
+// succeeds or ctx is done. A probeInterval <= 0 means one second.
+func WithHealthCheck(check func(ctx context.Context) error, probeInterval time.Duration) RetryOption {
+	if probeInterval <= 0 {
+		probeInterval = defaultProbeInterval
+	}
+	return func(r *retrier) {
+		r.healthCheck = check
+		r.probeInterval = probeInterval
+	}
+}
+
+// awaitHealthy waits until the health check passes before the given
+// attempt, if it is gated.
+func (r retrier) awaitHealthy(ctx context.Context, attempt int, stop <-chan struct{}) error {
+	if r.healthCheck == nil || attempt < healthCheckAfter {
+		return nil
+	}
+	timer := time.NewTimer(r.probeInterval)
+	defer timer.Stop()
+	for r.healthCheck(ctx) != nil {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-stop:
+			return ErrShutdown
+		case <-timer.C:
+			timer.Reset(r.probeInterval)
+		}
+	}
+	return nil
+}
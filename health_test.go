@@ -0,0 +1,64 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithHealthCheck(t *testing.T) {
+	var (
+		probes   int
+		attempts int
+	)
+	r := New(
+		WithMaxAttempts(4),
+		WithBackoff(FixedBackoff{}),
+		WithHealthCheck(func(context.Context) error {
+			probes++
+			if probes < 3 {
+				return errors.New("unhealthy")
+			}
+			return nil
+		}, time.Millisecond),
+	)
+
+	err := r.Do(context.Background(), func(int) error {
+		attempts++
+		if attempts < 3 {
+			return errAlwaysFail
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+	assert.Equal(t, 3, probes, "the probe gates attempts after two failures until it passes")
+}
+
+func TestWithHealthCheck_ContextDone(t *testing.T) {
+	r := New(
+		WithMaxAttempts(0),
+		WithBackoff(FixedBackoff{}),
+		WithHealthCheck(func(context.Context) error { return errors.New("unhealthy") }, time.Millisecond),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	attempts := 0
+	err := r.Do(ctx, func(int) error {
+		attempts++
+		return errAlwaysFail
+	})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, healthCheckAfter, attempts)
+}
+
+func TestWithHealthCheck_NonPositiveInterval(t *testing.T) {
+	for _, interval := range []time.Duration{0, -time.Second} {
+		r := New(WithHealthCheck(func(context.Context) error { return nil }, interval)).(*retrier)
+		assert.Equal(t, defaultProbeInterval, r.probeInterval)
+	}
+}
@@ -0,0 +1,49 @@
+package retry
+
+import (
+	"context"
+	"time"
+)
+
+// WithHeartbeat calls fn every interval while an attempt is running, with
+// the attempt number and how long it has been running, so monitoring can
+// tell a stuck attempt from a retrier sleeping between attempts. fn runs on
+// a separate goroutine and is not called after the attempt returns. An
+// interval <= 0 disables the heartbeat.
+func WithHeartbeat(interval time.Duration, fn func(attempt int, running time.Duration)) RetryOption {
+	if interval <= 0 {
+		return func(*retrier) {}
+	}
+	return WithAttemptMiddleware(func(next ContextAttemptFunc) ContextAttemptFunc {
+		return func(ctx context.Context, attempt int) error {
+			start := time.Now()
+			done := make(chan struct{})
+			stopped := make(chan struct{})
+			defer func() {
+				close(done)
+				<-stopped
+			}()
+			go func() {
+				defer close(stopped)
+
+				ticker := time.NewTicker(interval)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-done:
+						return
+					case now := <-ticker.C:
+						select {
+						case <-done:
+							return
+						default:
+						}
+						fn(attempt, now.Sub(start))
+					}
+				}
+			}()
+
+			return next(ctx, attempt)
+		}
+	})
+}
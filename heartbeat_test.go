@@ -0,0 +1,54 @@
+package retry
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithHeartbeat(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		beats = map[int][]time.Duration{}
+	)
+	r := New(
+		WithMaxAttempts(2),
+		WithBackoff(FixedBackoff{Interval: 20 * time.Millisecond}),
+		WithHeartbeat(5*time.Millisecond, func(attempt int, running time.Duration) {
+			mu.Lock()
+			beats[attempt] = append(beats[attempt], running)
+			mu.Unlock()
+		}),
+	)
+
+	_ = r.Do(context.Background(), func(attempt int) error {
+		if attempt == 0 {
+			time.Sleep(30 * time.Millisecond)
+		}
+		return errAlwaysFail
+	})
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.NotEmpty(t, beats[0], "a long attempt emits heartbeats")
+	assert.Empty(t, beats[1], "a fast attempt emits none")
+	for i := 1; i < len(beats[0]); i++ {
+		assert.Greater(t, beats[0][i], beats[0][i-1])
+	}
+}
+
+func TestWithHeartbeat_NonPositiveInterval(t *testing.T) {
+	for _, interval := range []time.Duration{0, -time.Second} {
+		r := New(WithHeartbeat(interval, func(int, time.Duration) {
+			t.Error("heartbeat called while disabled")
+		}))
+		err := r.Do(context.Background(), func(int) error {
+			time.Sleep(5 * time.Millisecond)
+			return nil
+		})
+		assert.NoError(t, err)
+	}
+}
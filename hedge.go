@@ -0,0 +1,66 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// HedgePolicy configures Hedge.
+type HedgePolicy struct {
+	// Delay is how long Hedge waits for the running attempts before
+	// launching another one.
+	Delay time.Duration
+	// MaxAttempts is the maximum number of attempts, including the first.
+	// Values below 2 mean 2.
+	MaxAttempts int
+}
+
+// Hedge runs f and, if it has not finished after policy.Delay, launches a
+// speculative duplicate attempt, and so on up to policy.MaxAttempts
+// concurrent attempts. A failed attempt launches the next one immediately.
+// Hedge returns nil at the first success and cancels the context of the
+// other attempts, which must honor it; if every attempt fails, it returns
+// their errors joined. Since attempts overlap, f must be safe to run
+// concurrently and, for writes, idempotent.
+func Hedge(ctx context.Context, policy HedgePolicy, f ContextAttemptFunc) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	attempts := max(policy.MaxAttempts, 2)
+	results := make(chan error, attempts)
+	launch := func(n int) {
+		actx := context.WithValue(ctx, attemptKey{}, Attempt{Number: n, Delay: time.Duration(n) * policy.Delay})
+		go func() { results <- f(actx, n) }()
+	}
+
+	timer := time.NewTimer(policy.Delay)
+	defer timer.Stop()
+
+	launch(0)
+	launched := 1
+	var errs []error
+	for len(errs) < launched {
+		select {
+		case err := <-results:
+			if err == nil {
+				return nil
+			}
+			errs = append(errs, err)
+			if launched < attempts && len(errs) == launched {
+				launch(launched)
+				launched++
+				timer.Reset(policy.Delay)
+			}
+		case <-timer.C:
+			if launched < attempts {
+				launch(launched)
+				launched++
+				timer.Reset(policy.Delay)
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return errors.Join(errs...)
+}
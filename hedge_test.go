@@ -0,0 +1,58 @@
+package retry
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHedge_SlowFirstAttempt(t *testing.T) {
+	var canceled atomic.Bool
+	err := Hedge(context.Background(), HedgePolicy{Delay: 10 * time.Millisecond}, func(ctx context.Context, attempt int) error {
+		if attempt == 0 {
+			<-ctx.Done()
+			canceled.Store(true)
+			return ctx.Err()
+		}
+		a, ok := AttemptFromContext(ctx)
+		assert.True(t, ok)
+		assert.Equal(t, 1, a.Number)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Eventually(t, canceled.Load, time.Second, time.Millisecond, "the loser is canceled")
+}
+
+func TestHedge_FastFirstAttempt(t *testing.T) {
+	var calls atomic.Int32
+	err := Hedge(context.Background(), HedgePolicy{Delay: time.Hour}, func(context.Context, int) error {
+		calls.Add(1)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestHedge_AllFail(t *testing.T) {
+	var calls atomic.Int32
+	err := Hedge(context.Background(), HedgePolicy{Delay: time.Hour, MaxAttempts: 3}, func(context.Context, int) error {
+		calls.Add(1)
+		return errAlwaysFail
+	})
+	assert.ErrorIs(t, err, errAlwaysFail)
+	assert.Equal(t, int32(3), calls.Load(), "failures launch the next attempt immediately")
+}
+
+func TestHedge_ContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := Hedge(ctx, HedgePolicy{Delay: time.Hour}, func(ctx context.Context, _ int) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,67 @@
+package retry
+
+import (
+	"errors"
+	"time"
+)
+
+// DelayHinter is implemented by errors that suggest how long to wait
+// before the next attempt, such as a server-provided Retry-After.
+//
+// When an attempt fails with an error whose chain contains a DelayHinter
+// reporting ok, the hinted delay is used instead of the backoff strategy,
+// capped by WithMaxDelayHint.
+type DelayHinter interface {
+	RetryDelay() (d time.Duration, ok bool)
+}
+
+// RetryAfter wraps err with a delay hint of d.
+// The returned error unwraps to err. A nil err yields nil.
+func RetryAfter(err error, d time.Duration) error {
+	if err == nil {
+		return nil
+	}
+	return &delayHintError{err: err, delay: d}
+}
+
+type delayHintError struct {
+	err   error
+	delay time.Duration
+}
+
+func (e *delayHintError) Error() string                     { return e.err.Error() }
+func (e *delayHintError) Unwrap() error                     { return e.err }
+func (e *delayHintError) RetryDelay() (time.Duration, bool) { return e.delay, e.delay >= 0 }
+
+// delayHint extracts a delay hint from err's chain, if any.
+func delayHint(err error) (time.Duration, bool) {
+	var h DelayHinter
+	if !errors.As(err, &h) {
+		return 0, false
+	}
+	return h.RetryDelay()
+}
+
+// WithMaxDelayHint caps the delays hinted by errors, so a server cannot
+// park a call for hours with a large Retry-After. The default is one
+// minute; a value <= 0 removes the cap.
+func WithMaxDelayHint(d time.Duration) RetryOption {
+	return func(r *retrier) {
+		r.maxDelayHint = d
+	}
+}
+
+// defaultMaxDelayHint returns the default cap of hinted delays.
+func defaultMaxDelayHint() time.Duration {
+	return time.Minute
+}
+
+// hintedDelay returns the delay hinted by err's chain, if any, capped by
+// the retrier's maximum.
+func (r retrier) hintedDelay(err error) (time.Duration, bool) {
+	d, ok := delayHint(err)
+	if ok && r.maxDelayHint > 0 {
+		d = min(d, r.maxDelayHint)
+	}
+	return d, ok
+}
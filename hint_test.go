@@ -0,0 +1,64 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryAfter(t *testing.T) {
+	assert.Nil(t, RetryAfter(nil, time.Second))
+
+	err := RetryAfter(errCustom, time.Second)
+	assert.ErrorIs(t, err, errCustom)
+	assert.EqualError(t, err, "custom error")
+
+	d, ok := delayHint(fmt.Errorf("wrapped: %w", err))
+	assert.True(t, ok)
+	assert.Equal(t, time.Second, d)
+
+	_, ok = delayHint(errCustom)
+	assert.False(t, ok)
+}
+
+func TestRetrier_DoHonorsDelayHint(t *testing.T) {
+	r := New(
+		WithMaxAttempts(2),
+		WithBackoff(FixedBackoff{Interval: time.Hour}),
+	)
+
+	start := time.Now()
+	err := r.Do(context.Background(), func(attempt int) error {
+		if attempt == 0 {
+			return RetryAfter(errAlwaysFail, time.Millisecond)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestWithMaxDelayHint(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []RetryOption
+		want time.Duration
+	}{
+		{name: "default", want: time.Minute},
+		{name: "custom", opts: []RetryOption{WithMaxDelayHint(time.Millisecond)}, want: time.Millisecond},
+		{name: "uncapped", opts: []RetryOption{WithMaxDelayHint(0)}, want: 999999 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New(tt.opts...).(*retrier)
+			d, ok := r.hintedDelay(RetryAfter(errAlwaysFail, 999999*time.Second))
+			assert.True(t, ok)
+			assert.Equal(t, tt.want, d)
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// hook observes the lifecycle of Do calls. Hooks are assembled once in New
+// from the configured options and called in order.
+type hook interface {
+	// attemptStarted is called before every attempt.
+	attemptStarted(ctx context.Context, attempt int)
+	// attemptFinished is called after every attempt with its error and duration.
+	attemptFinished(ctx context.Context, attempt int, err error, d time.Duration)
+	// retrying is called when a failed attempt will be retried after delay.
+	retrying(ctx context.Context, attempt int, err error, delay time.Duration)
+	// finished is called once per call with the number of attempts made
+	// and the error returned to the caller.
+	finished(ctx context.Context, attempts int, err error)
+}
+
+// nopHook implements hook with no-ops, for embedding.
+type nopHook struct{}
+
+func (nopHook) attemptStarted(context.Context, int)                        {}
+func (nopHook) attemptFinished(context.Context, int, error, time.Duration) {}
+func (nopHook) retrying(context.Context, int, error, time.Duration)        {}
+func (nopHook) finished(context.Context, int, error)                       {}
+
+// isExhausted reports whether err reports that all attempts failed.
+func isExhausted(err error) bool {
+	var e *MaxAttemptsError
+	return errors.As(err, &e)
+}
+
+// metricsHook adapts Metrics to hook.
+type metricsHook struct {
+	m Metrics
+}
+
+func (h metricsHook) attemptStarted(context.Context, int) { h.m.IncAttempt() }
+
+func (h metricsHook) attemptFinished(_ context.Context, _ int, _ error, d time.Duration) {
+	h.m.ObserveAttemptDuration(d)
+}
+
+func (h metricsHook) retrying(_ context.Context, _ int, _ error, delay time.Duration) {
+	h.m.IncRetry()
+	h.m.ObserveDelay(delay)
+}
+
+func (h metricsHook) finished(_ context.Context, attempts int, err error) {
+	switch {
+	case err == nil:
+		h.m.IncSuccess()
+		if attempts > 1 {
+			h.m.IncSuccessAfterRetry()
+		}
+		h.m.ObserveAttemptsToSuccess(attempts)
+	case isExhausted(err):
+		h.m.IncExhausted()
+	}
+}
+
+// onRetryHook adapts an OnRetryFunc to hook.
+type onRetryHook struct {
+	nopHook
+	fn OnRetryFunc
+}
+
+func (h onRetryHook) retrying(ctx context.Context, attempt int, err error, delay time.Duration) {
+	h.fn(ctx, attempt, err, delay)
+}
+
+// buildHooks assembles the hooks configured on r.
+func (r *retrier) buildHooks() {
+	r.hooks = nil
+	if _, ok := r.metrics.(NopMetrics); !ok {
+		r.hooks = append(r.hooks, metricsHook{m: r.metrics})
+	}
+	if r.logger != nil || r.loggerFromContext != nil {
+		r.hooks = append(r.hooks, sampledHook{h: slogHook{r: r}})
+	}
+	for _, fn := range r.onRetry {
+		r.hooks = append(r.hooks, onRetryHook{fn: fn})
+	}
+	r.hooks = append(r.hooks, r.extraHooks...)
+}
@@ -0,0 +1,131 @@
+package retry
+
+import (
+	"context"
+	"sync"
+)
+
+// Lifecycle is implemented by the retriers returned by New, which are
+// started on creation. Stop releases what a retrier holds once it is no
+// longer needed, e.g. at the end of a test or a short-lived job: it stops
+// new attempts, drains the calls in flight and removes the retrier from
+// the registry of named retriers. Start admits calls again after Stop.
+type Lifecycle interface {
+	// Start starts the retrier. Starting a started retrier does nothing.
+	Start(ctx context.Context) error
+	// Stop stops the retrier from scheduling new attempts, so calls
+	// started afterwards fail with ErrShutdown and calls in flight stop
+	// before their next attempt, and waits until their running attempts
+	// finish or ctx is done, in which case it returns the context error.
+	// Attempts are not canceled: bound them with WithAttemptTimeout for
+	// Stop to return in time.
+	Stop(ctx context.Context) error
+}
+
+// Start implements Lifecycle.
+func (r *retrier) Start(context.Context) error {
+	r.life.mu.Lock()
+	defer r.life.mu.Unlock()
+	if r.life.cur.isStopped() {
+		r.life.cur = newLifecycle()
+	}
+	if r.name != "" {
+		register(r)
+	}
+	return nil
+}
+
+// Stop implements Lifecycle.
+func (r *retrier) Stop(ctx context.Context) error {
+	r.life.mu.Lock()
+	l := r.life.cur
+	r.life.mu.Unlock()
+
+	if r.name != "" {
+		registry.CompareAndDelete(r.name, r)
+	}
+	return l.shutdown(ctx)
+}
+
+// lifeState holds the current run of a retrier.
+type lifeState struct {
+	mu  sync.Mutex
+	cur *lifecycle
+}
+
+// current returns the current run.
+func (s *lifeState) current() *lifecycle {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cur
+}
+
+// lifecycle is a run of a retrier, from its start to its stop.
+type lifecycle struct {
+	mu       sync.Mutex
+	stopping bool
+	calls    sync.WaitGroup
+	stop     chan struct{}
+}
+
+func newLifecycle() *lifecycle {
+	return &lifecycle{stop: make(chan struct{})}
+}
+
+// enter counts a call in flight, unless l is stopped.
+func (l *lifecycle) enter() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.stopping {
+		return false
+	}
+	l.calls.Add(1)
+	return true
+}
+
+// leave counts a call as finished.
+func (l *lifecycle) leave() { l.calls.Done() }
+
+// stopped returns a channel closed when l is stopped.
+func (l *lifecycle) stopped() <-chan struct{} { return l.stop }
+
+// isStopped reports whether l is stopped.
+func (l *lifecycle) isStopped() bool {
+	select {
+	case <-l.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// shutdown stops l and waits for its calls to finish or ctx to be done.
+func (l *lifecycle) shutdown(ctx context.Context) error {
+	l.halt()
+	return l.wait(ctx)
+}
+
+// halt stops l from admitting calls and scheduling attempts.
+func (l *lifecycle) halt() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.stopping {
+		l.stopping = true
+		close(l.stop)
+	}
+}
+
+// wait waits for the calls of l to finish or ctx to be done.
+func (l *lifecycle) wait(ctx context.Context) error {
+	drained := make(chan struct{})
+	go func() {
+		l.calls.Wait()
+		close(drained)
+	}()
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,58 @@
+package retry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetrier_StartStop(t *testing.T) {
+	r := New(WithName("lifecycle"), WithMaxAttempts(0), WithBackoff(FixedBackoff{Interval: time.Hour}))
+	lc, ok := r.(Lifecycle)
+	require.True(t, ok)
+
+	_, registered := registry.Load("lifecycle")
+	assert.True(t, registered)
+
+	sleeping := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- r.Do(context.Background(), func(attempt int) error {
+			if attempt == 0 {
+				close(sleeping)
+			}
+			return errAlwaysFail
+		})
+	}()
+	<-sleeping
+
+	require.NoError(t, lc.Stop(context.Background()))
+	err := <-done
+	assert.ErrorIs(t, err, ErrShutdown, "stopping wakes sleeping calls")
+	assert.ErrorIs(t, err, errAlwaysFail)
+	_, registered = registry.Load("lifecycle")
+	assert.False(t, registered, "stopped retriers are unregistered")
+	assert.ErrorIs(t, r.Do(context.Background(), func(int) error { return nil }), ErrShutdown)
+
+	require.NoError(t, lc.Start(context.Background()))
+	require.NoError(t, lc.Start(context.Background()))
+	assert.NoError(t, r.Do(context.Background(), func(int) error { return nil }))
+	_, registered = registry.Load("lifecycle")
+	assert.True(t, registered)
+	require.NoError(t, lc.Stop(context.Background()))
+}
+
+func TestManager_RestartedRetrier(t *testing.T) {
+	m := NewManager()
+	r := New(WithManager(m))
+	require.NoError(t, m.Shutdown(context.Background()))
+	assert.ErrorIs(t, r.Do(context.Background(), func(int) error { return nil }), ErrShutdown)
+
+	require.NoError(t, r.(Lifecycle).Start(context.Background()))
+	assert.NoError(t, r.Do(context.Background(), func(int) error { return nil }))
+	require.NoError(t, m.Shutdown(context.Background()))
+	assert.ErrorIs(t, r.Do(context.Background(), func(int) error { return nil }), ErrShutdown)
+}
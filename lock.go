@@ -0,0 +1,30 @@
+package retry
+
+import "context"
+
+// Locker is a distributed lock held by one process at a time. An etcd
+// *concurrency.Mutex implements it, and so does retryredis.Lock.
+type Locker interface {
+	// Lock blocks until the lock is held or ctx is done.
+	Lock(ctx context.Context) error
+	// Unlock releases the lock.
+	Unlock(ctx context.Context) error
+}
+
+// WithAttemptLock holds l around every attempt, for operations that must
+// not run concurrently on several replicas retrying the same work. A
+// failure to acquire l fails the attempt with the error of Lock. A failure
+// to release it is ignored: distributed locks expire on their own.
+func WithAttemptLock(l Locker) RetryOption {
+	return func(r *retrier) {
+		r.middleware = append(r.middleware, func(next ContextAttemptFunc) ContextAttemptFunc {
+			return func(ctx context.Context, attempt int) error {
+				if err := l.Lock(ctx); err != nil {
+					return err
+				}
+				defer func() { _ = l.Unlock(context.WithoutCancel(ctx)) }()
+				return next(ctx, attempt)
+			}
+		})
+	}
+}
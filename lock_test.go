@@ -0,0 +1,74 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// chanLocker is an in-process Locker.
+type chanLocker struct {
+	ch      chan struct{}
+	lockErr error
+}
+
+func newChanLocker() *chanLocker { return &chanLocker{ch: make(chan struct{}, 1)} }
+
+func (l *chanLocker) Lock(ctx context.Context) error {
+	if l.lockErr != nil {
+		return l.lockErr
+	}
+	select {
+	case l.ch <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (l *chanLocker) Unlock(context.Context) error {
+	<-l.ch
+	return nil
+}
+
+func TestWithAttemptLock(t *testing.T) {
+	l := newChanLocker()
+	var running, maxRunning atomic.Int32
+	attempt := func(int) error {
+		n := running.Add(1)
+		defer running.Add(-1)
+		if n > maxRunning.Load() {
+			maxRunning.Store(n)
+		}
+		time.Sleep(time.Millisecond)
+		return nil
+	}
+
+	var wg sync.WaitGroup
+	for range 3 {
+		r := New(WithAttemptLock(l))
+		wg.Go(func() { assert.NoError(t, r.Do(context.Background(), attempt)) })
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), maxRunning.Load(), "attempts never overlap")
+	assert.Empty(t, l.ch, "the lock is released")
+}
+
+func TestWithAttemptLock_LockError(t *testing.T) {
+	l := newChanLocker()
+	l.lockErr = errors.New("lock unavailable")
+	r := New(WithMaxAttempts(2), WithBackoff(FixedBackoff{}), WithAttemptLock(l))
+
+	called := false
+	err := r.Do(context.Background(), func(int) error {
+		called = true
+		return nil
+	})
+	assert.ErrorIs(t, err, l.lockErr)
+	assert.False(t, called)
+}
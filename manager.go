@@ -0,0 +1,77 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrShutdown is returned by Do calls of a stopped retrier, see Lifecycle
+// and Manager: calls started after the stop fail with it immediately, and
+// calls in flight stop before their next attempt with an error wrapping
+// both it and the last attempt error.
+var ErrShutdown = errors.New("retrier is shut down")
+
+// Manager tracks the in-flight Do calls of the retriers registered with
+// WithManager, so a service can drain them when it shuts down.
+type Manager struct {
+	mu       sync.Mutex
+	retriers []*retrier
+}
+
+// NewManager creates a Manager.
+func NewManager() *Manager {
+	return &Manager{}
+}
+
+// WithManager registers the retrier with m.
+func WithManager(m *Manager) RetryOption {
+	return func(r *retrier) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.retriers = append(m.retriers, r)
+	}
+}
+
+// Shutdown stops the registered retriers, as their Stop method does: they
+// no longer schedule new attempts, and Shutdown waits until their running
+// attempts finish, or until ctx is done, in which case it returns the
+// context error. Attempts are not canceled: bound them with
+// WithAttemptTimeout for Shutdown to return in time.
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.mu.Lock()
+	runs := make([]*lifecycle, len(m.retriers))
+	for i, r := range m.retriers {
+		runs[i] = r.life.current()
+	}
+	m.mu.Unlock()
+
+	for _, l := range runs {
+		l.halt()
+	}
+	for _, l := range runs {
+		if err := l.wait(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// shutdownError returns the error of a call stopped by shutdown after an
+// attempt failed with err.
+func shutdownError(err error) error {
+	if err == nil {
+		return ErrShutdown
+	}
+	return fmt.Errorf("%w: %w", ErrShutdown, err)
+}
+
+// waitError returns the error of a call whose wait for its next attempt
+// failed with waitErr, after an attempt failed with err.
+func waitError(waitErr, err error) error {
+	if waitErr == ErrShutdown {
+		return shutdownError(err)
+	}
+	return waitErr
+}
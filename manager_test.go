@@ -0,0 +1,79 @@
+package retry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_Shutdown(t *testing.T) {
+	m := NewManager()
+	r := New(
+		WithManager(m),
+		WithMaxAttempts(0),
+		WithBackoff(FixedBackoff{Interval: time.Hour}),
+	)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- r.Do(context.Background(), func(attempt int) error {
+			if attempt == 0 {
+				close(started)
+				<-release
+			}
+			return errAlwaysFail
+		})
+	}()
+	<-started
+
+	shutdown := make(chan error)
+	go func() { shutdown <- m.Shutdown(context.Background()) }()
+
+	select {
+	case <-shutdown:
+		t.Fatal("Shutdown returned while an attempt was running")
+	case <-time.After(10 * time.Millisecond):
+	}
+	close(release)
+
+	require.NoError(t, <-shutdown)
+	err := <-done
+	assert.ErrorIs(t, err, ErrShutdown, "the sleeping call stops")
+	assert.ErrorIs(t, err, errAlwaysFail)
+
+	err = r.Do(context.Background(), func(int) error {
+		t.Fatal("attempt ran after shutdown")
+		return nil
+	})
+	assert.ErrorIs(t, err, ErrShutdown)
+}
+
+func TestManager_ShutdownDeadline(t *testing.T) {
+	m := NewManager()
+	r := New(WithManager(m))
+
+	release := make(chan struct{})
+	defer close(release)
+	started := make(chan struct{})
+	go func() {
+		_ = r.Do(context.Background(), func(int) error {
+			close(started)
+			<-release
+			return nil
+		})
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, m.Shutdown(ctx), context.DeadlineExceeded)
+}
+
+func TestManager_Idle(t *testing.T) {
+	assert.NoError(t, NewManager().Shutdown(context.Background()))
+}
@@ -0,0 +1,75 @@
+package retry
+
+import "time"
+
+// Metrics receives measurements from a Retrier, so any monitoring system
+// can be plugged in without the package depending on one.
+//
+// Implementations must be safe for concurrent use.
+type Metrics interface {
+	// IncAttempt is called before every attempt.
+	IncAttempt()
+	// IncRetry is called when a failed attempt is going to be retried.
+	IncRetry()
+	// IncSuccess is called when a Do call succeeds.
+	IncSuccess()
+	// IncSuccessAfterRetry is called, after IncSuccess, when a Do call
+	// succeeds on a retry rather than on its first attempt.
+	IncSuccessAfterRetry()
+	// IncExhausted is called when a Do call runs out of attempts.
+	IncExhausted()
+	// ObserveDelay is called with the wait before each retry.
+	ObserveDelay(time.Duration)
+	// ObserveAttemptDuration is called with the duration of every attempt.
+	ObserveAttemptDuration(time.Duration)
+	// ObserveAttemptsToSuccess is called with the number of attempts a
+	// successful call needed, for tuning maxAttempts with data.
+	ObserveAttemptsToSuccess(int)
+	// AddInFlight is called with 1 when a call starts and -1 when it
+	// returns, for a gauge of calls in progress.
+	AddInFlight(delta int)
+	// AddSleeping is called with 1 when a call starts waiting between
+	// attempts and -1 when it stops, for a gauge of sleeping calls.
+	AddSleeping(delta int)
+}
+
+// NopMetrics is a Metrics implementation that discards all measurements.
+// It can be embedded to implement only part of the interface.
+type NopMetrics struct{}
+
+func (NopMetrics) IncAttempt()                          {}
+func (NopMetrics) IncRetry()                            {}
+func (NopMetrics) IncSuccess()                          {}
+func (NopMetrics) IncSuccessAfterRetry()                {}
+func (NopMetrics) IncExhausted()                        {}
+func (NopMetrics) ObserveDelay(time.Duration)           {}
+func (NopMetrics) ObserveAttemptDuration(time.Duration) {}
+func (NopMetrics) ObserveAttemptsToSuccess(int)         {}
+func (NopMetrics) AddInFlight(int)                      {}
+func (NopMetrics) AddSleeping(int)                      {}
+
+// WithMetrics sets the Metrics receiving the retrier's measurements.
+// A nil m disables metrics.
+func WithMetrics(m Metrics) RetryOption {
+	return func(r *retrier) {
+		if m == nil {
+			m = NopMetrics{}
+		}
+		r.metrics = m
+		r.metricsProvider = nil
+	}
+}
+
+// MetricsProvider creates the Metrics of a named retrier, e.g. by using the
+// name as a label. retryprom.Collector implements it.
+type MetricsProvider interface {
+	Metrics(name string) Metrics
+}
+
+// WithMetricsProvider records the retrier's measurements into the Metrics
+// p creates for the name set by WithName. It replaces WithMetrics.
+func WithMetricsProvider(p MetricsProvider) RetryOption {
+	return func(r *retrier) {
+		r.metricsProvider = p
+	}
+}
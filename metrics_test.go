@@ -0,0 +1,94 @@
+package retry
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingMetrics struct {
+	mu               sync.Mutex
+	attempts         int
+	retries          int
+	successes        int
+	exhausted        int
+	delays           []time.Duration
+	attemptDurations int
+	toSuccess        []int
+	retriedSuccesses int
+	inFlight         int
+	sleeping         int
+	maxSleeping      int
+}
+
+func (m *recordingMetrics) IncAttempt()           { m.mu.Lock(); m.attempts++; m.mu.Unlock() }
+func (m *recordingMetrics) IncRetry()             { m.mu.Lock(); m.retries++; m.mu.Unlock() }
+func (m *recordingMetrics) IncSuccess()           { m.mu.Lock(); m.successes++; m.mu.Unlock() }
+func (m *recordingMetrics) IncSuccessAfterRetry() { m.mu.Lock(); m.retriedSuccesses++; m.mu.Unlock() }
+func (m *recordingMetrics) IncExhausted()         { m.mu.Lock(); m.exhausted++; m.mu.Unlock() }
+
+func (m *recordingMetrics) ObserveDelay(d time.Duration) {
+	m.mu.Lock()
+	m.delays = append(m.delays, d)
+	m.mu.Unlock()
+}
+
+func (m *recordingMetrics) ObserveAttemptDuration(time.Duration) {
+	m.mu.Lock()
+	m.attemptDurations++
+	m.mu.Unlock()
+}
+
+func (m *recordingMetrics) ObserveAttemptsToSuccess(n int) {
+	m.mu.Lock()
+	m.toSuccess = append(m.toSuccess, n)
+	m.mu.Unlock()
+}
+
+func (m *recordingMetrics) AddInFlight(delta int) { m.mu.Lock(); m.inFlight += delta; m.mu.Unlock() }
+
+func (m *recordingMetrics) AddSleeping(delta int) {
+	m.mu.Lock()
+	m.sleeping += delta
+	m.maxSleeping = max(m.maxSleeping, m.sleeping)
+	m.mu.Unlock()
+}
+
+func TestRetrier_Metrics(t *testing.T) {
+	m := &recordingMetrics{}
+	r := New(
+		WithMaxAttempts(3),
+		WithBackoff(FixedBackoff{Interval: time.Millisecond}),
+		WithMetrics(m),
+	)
+
+	_ = r.Do(context.Background(), func(attempt int) error {
+		if attempt < 1 {
+			return errAlwaysFail
+		}
+		return nil
+	})
+	_ = r.Do(context.Background(), func(attempt int) error {
+		return errAlwaysFail
+	})
+
+	assert.Equal(t, 5, m.attempts)
+	assert.Equal(t, 5, m.attemptDurations)
+	assert.Equal(t, 3, m.retries, "no retry is scheduled after the last attempt")
+	assert.Equal(t, 1, m.successes)
+	assert.Equal(t, 1, m.retriedSuccesses)
+	assert.Equal(t, 1, m.exhausted)
+	assert.Equal(t, []int{2}, m.toSuccess)
+	assert.Zero(t, m.inFlight)
+	assert.Zero(t, m.sleeping)
+	assert.Equal(t, 1, m.maxSleeping)
+	assert.Equal(t, []time.Duration{time.Millisecond, time.Millisecond, time.Millisecond}, m.delays)
+}
+
+func TestWithMetricsNil(t *testing.T) {
+	r := New(WithMetrics(nil))
+	assert.NoError(t, r.Do(context.Background(), func(int) error { return nil }))
+}
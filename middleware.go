@@ -0,0 +1,45 @@
+package retry
+
+import (
+	"context"
+	"time"
+)
+
+// Attempt describes the attempt being executed.
+type Attempt struct {
+	// Number is the zero-based attempt number.
+	Number int
+	// Delay is the wait that preceded the attempt; zero for the first one.
+	Delay time.Duration
+}
+
+type attemptKey struct{}
+
+// AttemptFromContext returns the Attempt executing with ctx, if any.
+// The context passed to attempts and attempt middleware carries it.
+func AttemptFromContext(ctx context.Context) (Attempt, bool) {
+	a, ok := ctx.Value(attemptKey{}).(Attempt)
+	return a, ok
+}
+
+// AttemptMiddleware wraps the execution of every attempt, e.g. to start a
+// tracing span or set profiler labels. It may derive a new context for the
+// attempt; with Do, which runs attempts without a context, the derived
+// context is only visible to inner middleware.
+type AttemptMiddleware func(next ContextAttemptFunc) ContextAttemptFunc
+
+// WithAttemptMiddleware appends middleware wrapping every attempt.
+// The first middleware is the outermost.
+func WithAttemptMiddleware(mw ...AttemptMiddleware) RetryOption {
+	return func(r *retrier) {
+		r.middleware = append(r.middleware, mw...)
+	}
+}
+
+// wrap applies the configured middleware to f.
+func (r retrier) wrap(f ContextAttemptFunc) ContextAttemptFunc {
+	for i := len(r.middleware) - 1; i >= 0; i-- {
+		f = r.middleware[i](f)
+	}
+	return f
+}
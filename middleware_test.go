@@ -0,0 +1,46 @@
+package retry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type middlewareKey struct{}
+
+func TestWithAttemptMiddleware(t *testing.T) {
+	var order []string
+	trace := func(name string) AttemptMiddleware {
+		return func(next ContextAttemptFunc) ContextAttemptFunc {
+			return func(ctx context.Context, attempt int) error {
+				order = append(order, name)
+				return next(context.WithValue(ctx, middlewareKey{}, name), attempt)
+			}
+		}
+	}
+
+	var attempts []Attempt
+	r := New(
+		WithMaxAttempts(2),
+		WithBackoff(FixedBackoff{Interval: time.Millisecond}),
+		WithAttemptMiddleware(trace("outer"), trace("inner")),
+	)
+
+	err := r.DoContext(context.Background(), func(ctx context.Context, attempt int) error {
+		a, ok := AttemptFromContext(ctx)
+		assert.True(t, ok)
+		assert.Equal(t, attempt, a.Number)
+		assert.Equal(t, "inner", ctx.Value(middlewareKey{}))
+		attempts = append(attempts, a)
+		return errAlwaysFail
+	})
+
+	assert.Error(t, err)
+	assert.Equal(t, []string{"outer", "inner", "outer", "inner"}, order)
+	assert.Equal(t, []Attempt{{Number: 0}, {Number: 1, Delay: time.Millisecond}}, attempts)
+
+	_, ok := AttemptFromContext(context.Background())
+	assert.False(t, ok)
+}
@@ -0,0 +1,30 @@
+package retry
+
+import "context"
+
+// WithName names the retrier. The name labels its logs ("retrier"
+// attribute), events (Event.Retrier), profiler samples (PprofNameLabel)
+// and, with WithMetricsProvider, its metrics, so the retriers of a service
+// can be told apart. Hooks and middleware read it with NameFromContext.
+func WithName(name string) RetryOption {
+	return func(r *retrier) {
+		r.name = name
+	}
+}
+
+type nameKey struct{}
+
+// NameFromContext returns the name of the retrier running the Do call ctx
+// belongs to, if it has one.
+func NameFromContext(ctx context.Context) (string, bool) {
+	name, ok := ctx.Value(nameKey{}).(string)
+	return name, ok
+}
+
+// nameContext returns ctx carrying the name of the retrier, if any.
+func (r retrier) nameContext(ctx context.Context) context.Context {
+	if r.name == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, nameKey{}, r.name)
+}
@@ -0,0 +1,88 @@
+package retry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"runtime/pprof"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingProvider struct {
+	names []string
+	m     *recordingMetrics
+}
+
+func (p *recordingProvider) Metrics(name string) Metrics {
+	p.names = append(p.names, name)
+	return p.m
+}
+
+func TestWithName(t *testing.T) {
+	var buf bytes.Buffer
+	events := make(chan Event, 16)
+	provider := &recordingProvider{m: &recordingMetrics{}}
+
+	r := New(
+		WithName("payments"),
+		WithMaxAttempts(2),
+		WithBackoff(FixedBackoff{Interval: time.Millisecond}),
+		WithSlog(slog.New(slog.NewJSONHandler(&buf, nil)), slog.LevelInfo),
+		WithEventSink(events),
+		WithMetricsProvider(provider),
+		WithPprofLabels(),
+	)
+	_ = r.DoContext(context.Background(), func(ctx context.Context, attempt int) error {
+		v, ok := pprof.Label(ctx, PprofNameLabel)
+		assert.True(t, ok)
+		assert.Equal(t, "payments", v)
+		return errAlwaysFail
+	})
+
+	var rec map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &rec))
+	assert.Equal(t, "payments", rec["retrier"])
+
+	close(events)
+	for e := range events {
+		assert.Equal(t, "payments", e.Retrier)
+	}
+
+	assert.Equal(t, []string{"payments"}, provider.names)
+	assert.Equal(t, 2, provider.m.attempts)
+}
+
+func TestWithName_Unset(t *testing.T) {
+	var buf bytes.Buffer
+	r := New(
+		WithMaxAttempts(2),
+		WithBackoff(FixedBackoff{Interval: time.Millisecond}),
+		WithSlog(slog.New(slog.NewJSONHandler(&buf, nil)), slog.LevelInfo),
+		WithPprofLabels(),
+	)
+	_ = r.DoContext(context.Background(), func(ctx context.Context, attempt int) error {
+		_, ok := pprof.Label(ctx, PprofNameLabel)
+		assert.False(t, ok)
+		return errAlwaysFail
+	})
+
+	var rec map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &rec))
+	assert.NotContains(t, rec, "retrier")
+}
+
+func TestNameFromContext(t *testing.T) {
+	for _, name := range []string{"payments", ""} {
+		_ = New(WithName(name)).DoContext(context.Background(), func(ctx context.Context, _ int) error {
+			got, ok := NameFromContext(ctx)
+			assert.Equal(t, name != "", ok)
+			assert.Equal(t, name, got)
+			return nil
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError is a panic recovered from a function run by the package,
+// with the stack of the goroutine that panicked.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value any
+	// Stack is the stack trace of the panic, as returned by debug.Stack.
+	Stack []byte
+}
+
+func (e *PanicError) Error() string { return fmt.Sprintf("panic: %v", e.Value) }
+
+// Unwrap returns Value if it is an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// recovered runs f, converting a panic into a *PanicError.
+func recovered(f func() error) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = &PanicError{Value: p, Stack: debug.Stack()}
+		}
+	}()
+	return f()
+}
+
+// WithRecoverPanics makes a panic in an attempt fail the attempt with a
+// *PanicError instead of crashing the caller. The error goes through the
+// classifier like any other: see WithUnretryablePanics to never retry it.
+func WithRecoverPanics() RetryOption {
+	return func(r *retrier) {
+		r.recoverPanics = true
+	}
+}
+
+// WithUnretryablePanics is like WithRecoverPanics but a panicking attempt
+// is never retried, whatever the classifier: Do fails with its *PanicError
+// at once.
+func WithUnretryablePanics() RetryOption {
+	return func(r *retrier) {
+		r.recoverPanics = true
+		r.fatalPanics = true
+	}
+}
+
+// recoverAttempt returns f, converting its panics into errors if
+// WithRecoverPanics is set. It wraps the attempt function itself, inside
+// the middleware, so middleware sees a panic as a failed attempt.
+func (r retrier) recoverAttempt(f ContextAttemptFunc) ContextAttemptFunc {
+	if !r.recoverPanics {
+		return f
+	}
+	return func(ctx context.Context, attempt int) error {
+		return recovered(func() error { return f(ctx, attempt) })
+	}
+}
@@ -0,0 +1,104 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecovered(t *testing.T) {
+	assert.ErrorIs(t, recovered(func() error { return errAlwaysFail }), errAlwaysFail)
+
+	err := recovered(func() error { panic(errCustom) })
+	var pe *PanicError
+	assert.ErrorAs(t, err, &pe)
+	assert.Equal(t, "panic: "+errCustom.Error(), err.Error())
+	assert.ErrorIs(t, err, errCustom, "an error panic value is unwrapped")
+	assert.NotEmpty(t, pe.Stack)
+}
+
+func TestWithRecoverPanics(t *testing.T) {
+	calls := 0
+	var classified error
+	r := New(
+		WithMaxAttempts(3),
+		WithBackoff(FixedBackoff{}),
+		WithRecoverPanics(),
+		WithIsRetryableFunc(func(err error) bool {
+			classified = err
+			return true
+		}),
+	)
+	err := r.Do(context.Background(), func(int) error {
+		calls++
+		if calls < 3 {
+			panic("boom")
+		}
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+	var pe *PanicError
+	assert.ErrorAs(t, classified, &pe, "the panic goes through the classifier")
+	assert.Equal(t, "boom", pe.Value)
+	assert.Contains(t, string(pe.Stack), "panic_test.go")
+}
+
+func TestWithUnretryablePanics(t *testing.T) {
+	calls := 0
+	r := New(WithMaxAttempts(3), WithBackoff(FixedBackoff{}), WithUnretryablePanics())
+	err := r.Do(context.Background(), func(int) error {
+		calls++
+		panic("boom")
+	})
+
+	assert.Equal(t, 1, calls)
+	var ue *UnretryableError
+	assert.ErrorAs(t, err, &ue)
+	var pe *PanicError
+	assert.ErrorAs(t, err, &pe)
+
+	calls = 0
+	err = r.Do(context.Background(), func(int) error {
+		calls++
+		return errors.New("plain")
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 3, calls, "other errors are still retried")
+}
+
+func TestWithRecoverPanics_Middleware(t *testing.T) {
+	cb, clock := newTestBreaker(WithConsecutiveFailures(1), WithOpenTimeout(time.Minute))
+	cb.record(admit(t, cb), true)
+	clock.advance(time.Minute)
+
+	var beats atomic.Int32
+	r := New(
+		WithMaxAttempts(1),
+		WithRecoverPanics(),
+		WithCircuitBreaker(cb),
+		WithHeartbeat(time.Millisecond, func(int, time.Duration) { beats.Add(1) }),
+	)
+
+	err := r.Do(context.Background(), func(int) error {
+		time.Sleep(5 * time.Millisecond)
+		panic("boom")
+	})
+	var pe *PanicError
+	require.ErrorAs(t, err, &pe)
+	assert.Equal(t, CircuitOpen, cb.State(), "the panicking probe counts as a failure")
+
+	n := beats.Load()
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, n, beats.Load(), "the heartbeat stops with the attempt")
+
+	clock.advance(time.Minute)
+	assert.NoError(t, r.Do(context.Background(), func(int) error { return nil }))
+	assert.Equal(t, CircuitClosed, cb.State())
+}
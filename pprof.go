@@ -0,0 +1,36 @@
+package retry
+
+import (
+	"context"
+	"runtime/pprof"
+	"strconv"
+)
+
+// Profiler label keys set by WithPprofLabels.
+const (
+	PprofAttemptLabel = "retry.attempt"
+	PprofNameLabel    = "retry.name"
+)
+
+// WithPprofLabels sets pprof labels around every attempt, so CPU profiles
+// attribute the time spent in retried operations to the attempt number and,
+// if set with WithName, the retrier name.
+// Labels already present on the context are preserved.
+func WithPprofLabels() RetryOption {
+	return func(r *retrier) {
+		r.middleware = append(r.middleware, func(next ContextAttemptFunc) ContextAttemptFunc {
+			return func(ctx context.Context, attempt int) error {
+				labels := []string{PprofAttemptLabel, strconv.Itoa(attempt)}
+				if r.name != "" {
+					labels = append(labels, PprofNameLabel, r.name)
+				}
+
+				var err error
+				pprof.Do(ctx, pprof.Labels(labels...), func(ctx context.Context) {
+					err = next(ctx, attempt)
+				})
+				return err
+			}
+		})
+	}
+}
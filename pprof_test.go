@@ -0,0 +1,33 @@
+package retry
+
+import (
+	"context"
+	"runtime/pprof"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithPprofLabels(t *testing.T) {
+	r := New(
+		WithMaxAttempts(2),
+		WithBackoff(FixedBackoff{Interval: time.Millisecond}),
+		WithPprofLabels(),
+	)
+
+	ctx := pprof.WithLabels(context.Background(), pprof.Labels("handler", "sync"))
+	var got []string
+	_ = r.DoContext(ctx, func(ctx context.Context, attempt int) error {
+		v, ok := pprof.Label(ctx, PprofAttemptLabel)
+		assert.True(t, ok)
+		assert.Equal(t, strconv.Itoa(attempt), v)
+
+		h, ok := pprof.Label(ctx, "handler")
+		assert.True(t, ok)
+		got = append(got, h+"/"+v)
+		return errAlwaysFail
+	})
+	assert.Equal(t, []string{"sync/0", "sync/1"}, got)
+}
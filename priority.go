@@ -0,0 +1,61 @@
+package retry
+
+import "context"
+
+// WithPriorityFunc sets the function returning the priority of the call
+// carried by ctx, higher values being more important. Under load, calls
+// with a priority below a threshold skip their retries and return a
+// BudgetExceededError, so the remaining retry capacity goes to
+// high-priority calls, see WithPriorityShedding. By default, calls with a
+// priority below 1 are shed once the load reaches 0.8.
+func WithPriorityFunc(priority func(ctx context.Context) int) RetryOption {
+	return func(r *retrier) {
+		r.priority = priority
+	}
+}
+
+// WithPriorityShedding sets the load, from 0 to 1, from which calls with a
+// priority below minPriority skip their retries. The load is the highest
+// of the downstream pressure reported to WithBackpressure, the utilization
+// of the budgets set with WithBudget, WithBudgetStore and WithRetryQuota,
+// and 1 while the circuit breaker set with WithCircuitBreaker is not
+// closed.
+func WithPriorityShedding(load float64, minPriority int) RetryOption {
+	return func(r *retrier) {
+		r.shedLoad = load
+		r.shedBelow = minPriority
+	}
+}
+
+// defaultShedLoad and defaultShedBelow are the WithPriorityShedding
+// defaults.
+const (
+	defaultShedLoad  = 0.8
+	defaultShedBelow = 1
+)
+
+// shedByPriority reports whether the retry of the call carried by ctx is
+// skipped given its priority and the current load, which includes the
+// downstream pressure.
+func (r retrier) shedByPriority(ctx context.Context, pressure float64) bool {
+	if r.priority == nil || r.priority(ctx) >= r.shedBelow {
+		return false
+	}
+	return r.load(ctx, pressure) >= r.shedLoad
+}
+
+// load returns the current load of the retrier given the downstream
+// pressure.
+func (r retrier) load(ctx context.Context, pressure float64) float64 {
+	load := pressure
+	if b := r.callBudget(ctx); b != nil {
+		load = max(load, b.Utilization())
+	}
+	if r.quota != nil {
+		load = max(load, r.quota.Utilization())
+	}
+	if r.breaker != nil && r.breaker.State() != CircuitClosed {
+		load = 1
+	}
+	return load
+}
@@ -0,0 +1,78 @@
+package retry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type priorityKey struct{}
+
+func TestWithPriorityFunc(t *testing.T) {
+	tests := []struct {
+		name      string
+		spent     int
+		priority  int
+		wantCalls int
+	}{
+		{name: "low priority under load", spent: 8, priority: 0, wantCalls: 1},
+		{name: "high priority under load", spent: 8, priority: 1, wantCalls: 2},
+		{name: "low priority without load", spent: 7, priority: 0, wantCalls: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, _ := newTestBudget(0, time.Minute, WithMinRetries(10))
+			for range tt.spent {
+				b.tryRetry(1)
+			}
+			r := New(
+				WithMaxAttempts(2),
+				WithBackoff(FixedBackoff{}),
+				WithBudget(b),
+				WithPriorityFunc(func(ctx context.Context) int {
+					p, _ := ctx.Value(priorityKey{}).(int)
+					return p
+				}),
+			)
+
+			calls := 0
+			ctx := context.WithValue(context.Background(), priorityKey{}, tt.priority)
+			err := r.Do(ctx, func(int) error {
+				calls++
+				return errAlwaysFail
+			})
+			assert.Equal(t, tt.wantCalls, calls)
+			if tt.wantCalls == 1 {
+				assert.ErrorIs(t, err, ErrBudgetExceeded)
+			}
+		})
+	}
+}
+
+func TestWithPriorityShedding(t *testing.T) {
+	cb, _ := newTestBreaker(WithConsecutiveFailures(1))
+	cb.record(admit(t, cb), true)
+	r := New(
+		WithPriorityFunc(func(context.Context) int { return 5 }),
+		WithPriorityShedding(0.5, 10),
+		WithCircuitBreaker(cb),
+	).(*retrier)
+
+	assert.Equal(t, 1.0, r.load(context.Background(), 0))
+	assert.True(t, r.shedByPriority(context.Background(), 0))
+}
+
+func TestBudget_Utilization(t *testing.T) {
+	b, _ := newTestBudget(0.5, time.Minute, WithMinRetries(0))
+	assert.Equal(t, 1.0, b.Utilization(), "a budget without calls has no room")
+
+	for range 4 {
+		b.recordCall()
+	}
+	assert.Equal(t, 0.0, b.Utilization())
+	b.tryRetry(1)
+	assert.Equal(t, 0.5, b.Utilization())
+}
@@ -0,0 +1,39 @@
+package retry
+
+import (
+	"context"
+	"errors"
+)
+
+// Race runs every function of fns concurrently, each retried with the
+// default configuration, e.g. to fetch the same object from several
+// mirrors. It returns nil at the first success, after which the others
+// stop retrying, and their errors joined otherwise.
+func Race(ctx context.Context, fns ...AttemptFunc) error {
+	return RaceWith(ctx, New(), fns...)
+}
+
+// RaceWith is like Race but retries every function of fns with r.
+func RaceWith(ctx context.Context, r Retrier, fns ...AttemptFunc) error {
+	if len(fns) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan error, len(fns))
+	for _, f := range fns {
+		go func() { results <- r.Do(ctx, f) }()
+	}
+
+	errs := make([]error, 0, len(fns))
+	for range fns {
+		err := <-results
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,47 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRaceWith(t *testing.T) {
+	r := New(WithMaxAttempts(3), WithBackoff(FixedBackoff{Interval: time.Millisecond}))
+
+	t.Run("first success wins", func(t *testing.T) {
+		err := RaceWith(context.Background(), r,
+			func(int) error { return errAlwaysFail },
+			func(attempt int) error {
+				if attempt < 1 {
+					return errCustom
+				}
+				return nil
+			},
+		)
+		require.NoError(t, err)
+	})
+
+	t.Run("all fail", func(t *testing.T) {
+		errMirror := errors.New("mirror down")
+		err := RaceWith(context.Background(), r,
+			func(int) error { return errAlwaysFail },
+			func(int) error { return errMirror },
+		)
+		assert.ErrorIs(t, err, errAlwaysFail)
+		assert.ErrorIs(t, err, errMirror)
+	})
+
+	t.Run("no functions", func(t *testing.T) {
+		assert.NoError(t, RaceWith(context.Background(), r))
+	})
+}
+
+func TestRace(t *testing.T) {
+	err := Race(context.Background(), func(int) error { return nil })
+	assert.NoError(t, err)
+}
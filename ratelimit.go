@@ -0,0 +1,21 @@
+package retry
+
+import "context"
+
+// Limiter gates attempts. *rate.Limiter from golang.org/x/time/rate
+// implements it.
+type Limiter interface {
+	// Wait blocks until an attempt may proceed, or returns an error if it
+	// cannot proceed before ctx is done.
+	Wait(ctx context.Context) error
+}
+
+// WithRateLimiter makes every attempt, including the first one, wait for l
+// before running. Sharing l between retriers and goroutines caps the
+// aggregate attempt rate against a dependency regardless of concurrency.
+// If l returns an error, the call stops and returns it.
+func WithRateLimiter(l Limiter) RetryOption {
+	return func(r *retrier) {
+		r.limiter = l
+	}
+}
@@ -0,0 +1,46 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type countingLimiter struct {
+	waits atomic.Int32
+	err   error
+}
+
+func (l *countingLimiter) Wait(context.Context) error {
+	l.waits.Add(1)
+	return l.err
+}
+
+func TestWithRateLimiter(t *testing.T) {
+	l := &countingLimiter{}
+	r := New(
+		WithMaxAttempts(3),
+		WithBackoff(FixedBackoff{Interval: time.Millisecond}),
+		WithRateLimiter(l),
+	)
+	_ = r.Do(context.Background(), func(attempt int) error { return errAlwaysFail })
+	assert.Equal(t, int32(3), l.waits.Load())
+}
+
+func TestWithRateLimiter_Error(t *testing.T) {
+	errLimited := errors.New("rate: Wait(n=1) would exceed context deadline")
+	l := &countingLimiter{err: errLimited}
+	r := New(WithRateLimiter(l))
+
+	calls := 0
+	err := r.Do(context.Background(), func(attempt int) error {
+		calls++
+		return nil
+	})
+	assert.ErrorIs(t, err, errLimited)
+	assert.Zero(t, calls)
+}
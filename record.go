@@ -0,0 +1,166 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Recording is the timeline of a Do call captured by a Recorder. It
+// serializes to JSON, so timelines can be stored with bug reports and
+// replayed in tests.
+type Recording struct {
+	Steps []RecordedStep `json:"steps"`
+	// Result is the message of the error returned by the call, empty if it
+	// succeeded.
+	Result string `json:"result,omitempty"`
+}
+
+// RecordedStep is an attempt of a recorded call.
+type RecordedStep struct {
+	Attempt int `json:"attempt"`
+	// Offset is when the attempt started, relative to the start of the call.
+	Offset time.Duration `json:"offset_ns"`
+	// Error is the message of the attempt error, empty if it succeeded.
+	Error string `json:"error,omitempty"`
+	// Delay is the wait before the next attempt, including jitter.
+	Delay time.Duration `json:"delay_ns,omitempty"`
+}
+
+// Recorder captures the timeline of Do calls. Concurrent calls are
+// recorded separately, and their recordings are kept in the order the
+// calls finish.
+type Recorder struct {
+	nopHook
+
+	mu         sync.Mutex
+	recordings []Recording
+}
+
+// recordingKey is the context key of the call recording of rec.
+type recordingKey struct{ rec *Recorder }
+
+// callRecording is the recording of a call in progress, guarded by the
+// mutex of its Recorder.
+type callRecording struct {
+	start time.Time
+	Recording
+}
+
+// NewRecorder creates an empty Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// WithRecorder records the timeline of every Do call into rec.
+func WithRecorder(rec *Recorder) RetryOption {
+	return func(r *retrier) {
+		r.extraHooks = append(r.extraHooks, rec)
+		r.recorders = append(r.recorders, rec)
+	}
+}
+
+// startRecordings returns ctx carrying a new call recording for every
+// Recorder of the retrier.
+func (r retrier) startRecordings(ctx context.Context) context.Context {
+	for _, rec := range r.recorders {
+		ctx = context.WithValue(ctx, recordingKey{rec}, &callRecording{start: time.Now()})
+	}
+	return ctx
+}
+
+// call returns the recording of the call carried by ctx, nil if it is not
+// recorded.
+func (rec *Recorder) call(ctx context.Context) *callRecording {
+	c, _ := ctx.Value(recordingKey{rec}).(*callRecording)
+	return c
+}
+
+// Recordings returns the timelines of the calls finished so far.
+func (rec *Recorder) Recordings() []Recording {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return append([]Recording(nil), rec.recordings...)
+}
+
+func (rec *Recorder) attemptStarted(ctx context.Context, attempt int) {
+	c := rec.call(ctx)
+	if c == nil {
+		return
+	}
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	c.Steps = append(c.Steps, RecordedStep{
+		Attempt: attempt,
+		Offset:  time.Since(c.start),
+	})
+}
+
+func (rec *Recorder) attemptFinished(ctx context.Context, _ int, err error, _ time.Duration) {
+	c := rec.call(ctx)
+	if c == nil || err == nil {
+		return
+	}
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if n := len(c.Steps); n > 0 {
+		c.Steps[n-1].Error = err.Error()
+	}
+}
+
+func (rec *Recorder) retrying(ctx context.Context, _ int, _ error, delay time.Duration) {
+	c := rec.call(ctx)
+	if c == nil {
+		return
+	}
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if n := len(c.Steps); n > 0 {
+		c.Steps[n-1].Delay = delay
+	}
+}
+
+func (rec *Recorder) finished(ctx context.Context, _ int, err error) {
+	c := rec.call(ctx)
+	if c == nil {
+		return
+	}
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if err != nil {
+		c.Result = err.Error()
+	}
+	rec.recordings = append(rec.recordings, c.Recording)
+}
+
+// Backoff returns a Backoff reproducing the recorded delays.
+func (r Recording) Backoff() Backoff {
+	delays := make([]time.Duration, len(r.Steps))
+	for i, s := range r.Steps {
+		delays[i] = s.Delay
+	}
+	return replayBackoff(delays)
+}
+
+// Attempt returns an AttemptFunc reproducing the recorded attempt errors.
+// Errors are recreated from their messages, so only their text matches
+// the original ones. Attempts past the recording succeed.
+func (r Recording) Attempt() AttemptFunc {
+	return func(attempt int) error {
+		if attempt < len(r.Steps) && r.Steps[attempt].Error != "" {
+			return errors.New(r.Steps[attempt].Error)
+		}
+		return nil
+	}
+}
+
+// replayBackoff returns recorded delays by attempt.
+type replayBackoff []time.Duration
+
+func (b replayBackoff) Next(attempt int) time.Duration {
+	if attempt < len(b) {
+		return b[attempt]
+	}
+	return 0
+}
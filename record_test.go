@@ -0,0 +1,98 @@
+package retry
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder(t *testing.T) {
+	rec := NewRecorder()
+	r := New(
+		WithMaxAttempts(3),
+		WithBackoff(ExponentialBackoff{Base: time.Millisecond, Factor: 2, Jitter: 0.5}),
+		WithRecorder(rec),
+	)
+	err := r.Do(context.Background(), func(attempt int) error { return errAlwaysFail })
+	require.Error(t, err)
+	_ = r.Do(context.Background(), func(attempt int) error { return nil })
+
+	recordings := rec.Recordings()
+	require.Len(t, recordings, 2)
+
+	got := recordings[0]
+	assert.Equal(t, err.Error(), got.Result)
+	require.Len(t, got.Steps, 3)
+	for i, s := range got.Steps {
+		assert.Equal(t, i, s.Attempt)
+		assert.Equal(t, "always fail", s.Error)
+	}
+	assert.Positive(t, got.Steps[0].Delay)
+	assert.Zero(t, got.Steps[2].Delay)
+	assert.GreaterOrEqual(t, got.Steps[1].Offset, got.Steps[0].Delay)
+
+	assert.Equal(t, Recording{Steps: []RecordedStep{{Attempt: 0, Offset: recordings[1].Steps[0].Offset}}}, recordings[1])
+}
+
+func TestRecorder_Concurrent(t *testing.T) {
+	rec := NewRecorder()
+	r := New(
+		WithMaxAttempts(3),
+		WithBackoff(FixedBackoff{Interval: time.Millisecond}),
+		WithRecorder(rec),
+	)
+
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Go(func() {
+			_ = r.Do(context.Background(), func(attempt int) error {
+				if attempt < i%3 {
+					return errAlwaysFail
+				}
+				return nil
+			})
+		})
+	}
+	wg.Wait()
+
+	recordings := rec.Recordings()
+	require.Len(t, recordings, 50)
+	for _, got := range recordings {
+		last := len(got.Steps) - 1
+		for i, s := range got.Steps {
+			assert.Equal(t, i, s.Attempt, "each recording holds the attempts of one call")
+			assert.Equal(t, i < last, s.Error != "")
+		}
+		assert.Empty(t, got.Result)
+	}
+}
+
+func TestRecording_Replay(t *testing.T) {
+	data := `{"steps":[
+		{"attempt":0,"offset_ns":0,"error":"connection reset","delay_ns":1000000},
+		{"attempt":1,"offset_ns":1000000,"error":"connection reset","delay_ns":2000000},
+		{"attempt":2,"offset_ns":3000000}
+	]}`
+	var recording Recording
+	require.NoError(t, json.Unmarshal([]byte(data), &recording))
+
+	rec := NewRecorder()
+	r := New(
+		WithMaxAttempts(5),
+		WithBackoff(recording.Backoff()),
+		WithRecorder(rec),
+	)
+	require.NoError(t, r.Do(context.Background(), recording.Attempt()))
+
+	replayed := rec.Recordings()[0]
+	require.Len(t, replayed.Steps, 3)
+	for i, s := range replayed.Steps {
+		assert.Equal(t, recording.Steps[i].Error, s.Error)
+		assert.Equal(t, recording.Steps[i].Delay, s.Delay)
+	}
+}
@@ -0,0 +1,104 @@
+// Package resilience composes the retry package's building blocks, such as
+// timeouts, retriers, circuit breakers and bulkheads, into pipelines
+// declared once and shared by every call to a dependency:
+//
+//	p := resilience.New(
+//		resilience.Timeout(5*time.Second),
+//		resilience.Retry(retry.New(retry.WithMaxAttempts(3))),
+//		resilience.CircuitBreaker(cb),
+//		resilience.Bulkhead(10, 100),
+//	)
+//	err := p.Do(ctx, func(ctx context.Context) error { ... })
+package resilience
+
+import (
+	"context"
+	"time"
+
+	"github.com/er-davo/retry"
+)
+
+// ErrBulkheadFull is returned when a bulkhead rejects a call because its
+// concurrency limit is reached and no waiting slot is free. It is the error
+// returned by retriers limited with retry.WithMaxConcurrent.
+var ErrBulkheadFull = retry.ErrBulkheadFull
+
+// Func is an operation run by a Pipeline.
+type Func func(context.Context) error
+
+// Stage wraps the rest of a pipeline.
+type Stage func(next Func) Func
+
+// Pipeline runs operations through a sequence of stages. The first stage
+// is the outermost: with Timeout, Retry, CircuitBreaker and Bulkhead in
+// that order, the timeout bounds the whole call and every retried attempt
+// goes through the circuit breaker and the bulkhead.
+type Pipeline struct {
+	stages []Stage
+}
+
+// New creates a Pipeline from stages.
+func New(stages ...Stage) *Pipeline {
+	return &Pipeline{stages: stages}
+}
+
+// Do runs f through the pipeline's stages.
+func (p *Pipeline) Do(ctx context.Context, f Func) error {
+	for i := len(p.stages) - 1; i >= 0; i-- {
+		f = p.stages[i](f)
+	}
+	return f(ctx)
+}
+
+// Timeout bounds the rest of the pipeline to d.
+func Timeout(d time.Duration) Stage {
+	return func(next Func) Func {
+		return func(ctx context.Context) error {
+			ctx, cancel := context.WithTimeout(ctx, d)
+			defer cancel()
+			return next(ctx)
+		}
+	}
+}
+
+// HardTimeout bounds the rest of the pipeline to d even if it ignores its
+// context, see retry.WithTimeoutStage.
+func HardTimeout(d time.Duration) Stage {
+	timeout := retry.WithTimeoutStage(d)
+	return func(next Func) Func {
+		return Func(timeout(next))
+	}
+}
+
+// Retry runs the rest of the pipeline as the attempts of r.
+func Retry(r retry.ContextRetrier) Stage {
+	return func(next Func) Func {
+		return func(ctx context.Context) error {
+			return r.DoContext(ctx, func(ctx context.Context, _ int) error {
+				return next(ctx)
+			})
+		}
+	}
+}
+
+// CircuitBreaker guards the rest of the pipeline with cb, failing fast
+// with retry.ErrCircuitOpen while it is open. Errors returned by the rest
+// of the pipeline count as failures.
+func CircuitBreaker(cb *retry.CircuitBreaker) Stage {
+	return func(next Func) Func {
+		return func(ctx context.Context) error {
+			return cb.Do(ctx, next)
+		}
+	}
+}
+
+// Bulkhead limits the rest of the pipeline to n concurrent executions, see
+// retry.WithBulkheadStage: up to maxWaiting calls over the limit wait for a
+// slot until their context is done, and the others fail with
+// ErrBulkheadFull.
+func Bulkhead(n, maxWaiting int) Stage {
+	bulkhead := retry.WithBulkheadStage(n, maxWaiting)
+	return func(next Func) Func {
+		return Func(bulkhead(next))
+	}
+}
@@ -0,0 +1,144 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/er-davo/retry"
+	"github.com/stretchr/testify/assert"
+)
+
+var errBoom = errors.New("boom")
+
+func TestPipeline_Order(t *testing.T) {
+	var order []string
+	stage := func(name string) Stage {
+		return func(next Func) Func {
+			return func(ctx context.Context) error {
+				order = append(order, name)
+				return next(ctx)
+			}
+		}
+	}
+
+	p := New(stage("outer"), stage("inner"))
+	assert.NoError(t, p.Do(context.Background(), func(context.Context) error {
+		order = append(order, "f")
+		return nil
+	}))
+	assert.Equal(t, []string{"outer", "inner", "f"}, order)
+}
+
+func TestPipeline(t *testing.T) {
+	cb := retry.NewCircuitBreaker(retry.WithConsecutiveFailures(2), retry.WithOpenTimeout(time.Minute))
+	p := New(
+		Timeout(time.Second),
+		Retry(retry.New(retry.WithMaxAttempts(5), retry.WithBackoff(retry.FixedBackoff{}))),
+		CircuitBreaker(cb),
+		Bulkhead(1, 0),
+	)
+
+	calls := 0
+	err := p.Do(context.Background(), func(ctx context.Context) error {
+		_, ok := ctx.Deadline()
+		assert.True(t, ok, "the timeout applies inside")
+		calls++
+		return errBoom
+	})
+	assert.ErrorIs(t, err, retry.ErrCircuitOpen)
+	assert.Equal(t, 2, calls, "the open circuit stops retries")
+	assert.Equal(t, retry.CircuitOpen, cb.State())
+}
+
+func TestTimeout(t *testing.T) {
+	p := New(Timeout(time.Millisecond))
+	err := p.Do(context.Background(), func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestHardTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	p := New(HardTimeout(time.Millisecond))
+	err := p.Do(context.Background(), func(context.Context) error {
+		<-release
+		return nil
+	})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestBulkhead(t *testing.T) {
+	p := New(Bulkhead(2, 10))
+
+	var (
+		running, peak atomic.Int32
+		wg            sync.WaitGroup
+	)
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = p.Do(context.Background(), func(context.Context) error {
+				n := running.Add(1)
+				for {
+					old := peak.Load()
+					if n <= old || peak.CompareAndSwap(old, n) {
+						break
+					}
+				}
+				time.Sleep(time.Millisecond)
+				running.Add(-1)
+				return nil
+			})
+		}()
+	}
+	wg.Wait()
+	assert.LessOrEqual(t, peak.Load(), int32(2))
+}
+
+func TestBulkhead_Full(t *testing.T) {
+	p := New(Bulkhead(1, 1))
+	release := make(chan struct{})
+	started := make(chan struct{})
+	go func() {
+		_ = p.Do(context.Background(), func(context.Context) error {
+			close(started)
+			<-release
+			return nil
+		})
+	}()
+	<-started
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	err := p.Do(ctx, func(context.Context) error { return nil })
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "the call waited for a slot")
+	assert.NotErrorIs(t, err, ErrBulkheadFull)
+}
+
+func TestBulkhead_QueueFull(t *testing.T) {
+	p := New(Bulkhead(1, 0))
+	release := make(chan struct{})
+	started := make(chan struct{})
+	go func() {
+		_ = p.Do(context.Background(), func(context.Context) error {
+			close(started)
+			<-release
+			return nil
+		})
+	}()
+	<-started
+	defer close(release)
+
+	err := p.Do(context.Background(), func(context.Context) error { return nil })
+	assert.ErrorIs(t, err, ErrBulkheadFull)
+}
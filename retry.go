@@ -2,10 +2,16 @@ package retry
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"time"
 )
 
+// ErrAttemptTimeout is reported when an attempt exceeds the timeout set by
+// WithAttemptTimeout while the parent context is still active.
+var ErrAttemptTimeout = errors.New("attempt timed out")
+
 type noRetrier struct{}
 
 // Do executes the provided AttemptFunc once.
@@ -13,8 +19,13 @@ func (n noRetrier) Do(ctx context.Context, f AttemptFunc) error {
 	return f(0)
 }
 
+// DoContext executes the provided ContextAttemptFunc once.
+func (n noRetrier) DoContext(ctx context.Context, f ContextAttemptFunc) error {
+	return f(ctx, 0)
+}
+
 // NoRetry returns a Retrier that executes the operation once.
-func NoRetry() Retrier {
+func NoRetry() ContextRetrier {
 	return &noRetrier{}
 }
 
@@ -26,6 +37,14 @@ type RetryOption func(*retrier)
 // Returning nil indicates success; a non-nil error triggers retry logic.
 type AttemptFunc func(int) error
 
+// ContextAttemptFunc is like AttemptFunc but also receives the context of
+// the attempt, which carries the per-attempt timeout if one is configured.
+type ContextAttemptFunc func(context.Context, int) error
+
+// OnRetryFunc is called after a failed attempt that will be retried,
+// before waiting delay for the next one.
+type OnRetryFunc func(ctx context.Context, attempt int, err error, delay time.Duration)
+
 // IsRetryableFunc determines whether an error is retryable.
 // Returning false stops retries immediately.
 type IsRetryableFunc func(error) bool
@@ -37,10 +56,96 @@ type Retrier interface {
 	Do(context.Context, AttemptFunc) error
 }
 
+// ContextRetrier is a Retrier that can also pass each attempt its own
+// context. The retriers returned by New and NoRetry implement it.
+type ContextRetrier interface {
+	Retrier
+
+	// DoContext is like Do but passes each attempt its own context.
+	DoContext(context.Context, ContextAttemptFunc) error
+}
+
+// AsContextRetrier returns r if it is a ContextRetrier, or else a
+// ContextRetrier running the attempts with r.Do, passing each of them the
+// context of the call. It returns nil if r is nil.
+func AsContextRetrier(r Retrier) ContextRetrier {
+	if r == nil {
+		return nil
+	}
+	if cr, ok := r.(ContextRetrier); ok {
+		return cr
+	}
+	return contextRetrier{r}
+}
+
+// contextRetrier adapts a Retrier to ContextRetrier.
+type contextRetrier struct {
+	Retrier
+}
+
+func (r contextRetrier) DoContext(ctx context.Context, f ContextAttemptFunc) error {
+	return r.Do(ctx, func(attempt int) error {
+		return f(ctx, attempt)
+	})
+}
+
 type retrier struct {
-	backoff     Backoff
-	maxAttempts int
-	isRetryable IsRetryableFunc
+	name            string
+	backoff         Backoff
+	maxDelayHint    time.Duration
+	maxAttempts     int
+	isRetryable     IsRetryableFunc
+	retryableDesc   string
+	attemptTimeout  time.Duration
+	leakGrace       time.Duration
+	leakFunc        LeakFunc
+	aggregateErrors bool
+	recoverPanics   bool
+	fatalPanics     bool
+	attemptTrace    bool
+	sampling        bool
+	sampleRate      float64
+	correlationID   func() string
+	runtimeTrace    bool
+	bulkhead        *bulkhead
+	limiter         Limiter
+	budget          *Budget
+	budgetStore     *BudgetStore
+	budgetKey       func(context.Context) string
+	quota           *Budget
+	sloGates        []SLOGate
+	attemptCost     func(attempt int) float64
+	backpressure    func() float64
+	breaker         *CircuitBreaker
+	healthCheck     func(context.Context) error
+	probeInterval   time.Duration
+	active          func() bool
+	priority        func(context.Context) int
+	shedLoad        float64
+	shedBelow       int
+	metrics         Metrics
+	metricsProvider MetricsProvider
+	middleware      []AttemptMiddleware
+	onRetry         []OnRetryFunc
+	recorders       []*Recorder
+	extraHooks      []hook
+	stats           *statsHook
+	live            *liveState
+	hooks           []hook
+
+	logger            *slog.Logger
+	logLevel          slog.Level
+	loggerFromContext func(context.Context) *slog.Logger
+
+	tierFunc        TierFunc
+	tierMultipliers map[Tier]float64
+
+	sticky *stickyTarget
+	life   *lifeState
+
+	dedupKey func(context.Context) string
+	dedup    *dedupGroup
+	cache    *resultCache
 }
 
 // New creates a new Retrier with optional configuration.
@@ -48,16 +153,33 @@ type retrier struct {
 //   - a linear backoff
 //   - a maximum of 3 attempts
 //   - a retryable check that retries on any non-nil error
-func New(opts ...RetryOption) Retrier {
+func New(opts ...RetryOption) ContextRetrier {
 	r := &retrier{
-		backoff:     defaultBackoff(),
-		maxAttempts: defaultAttempts(),
-		isRetryable: defaultIsRetryableFunc(),
+		backoff:       defaultBackoff(),
+		maxDelayHint:  defaultMaxDelayHint(),
+		maxAttempts:   defaultAttempts(),
+		isRetryable:   defaultIsRetryableFunc(),
+		retryableDesc: "retry on any error",
+		metrics:       NopMetrics{},
+		live:          &liveState{},
+		life:          &lifeState{cur: newLifecycle()},
+		logLevel:      slog.LevelInfo,
+		shedLoad:      defaultShedLoad,
+		shedBelow:     defaultShedBelow,
+
+		tierMultipliers: defaultTierMultipliers(),
 	}
 
 	for _, opt := range opts {
 		opt(r)
 	}
+	if r.metricsProvider != nil {
+		r.metrics = r.metricsProvider.Metrics(r.name)
+	}
+	r.buildHooks()
+	if r.name != "" {
+		register(r)
+	}
 
 	return r
 }
@@ -67,32 +189,239 @@ func New(opts ...RetryOption) Retrier {
 // The function:
 //   - stops immediately if the context is canceled
 //   - retries while attempts remain (or indefinitely if maxAttempts == 0)
-//   - applies the configured backoff between attempts
+//   - applies the configured backoff between attempts, unless the error
+//     carries a delay hint (see DelayHinter)
 //   - stops early if an error is deemed non-retryable
 func (r retrier) Do(ctx context.Context, f AttemptFunc) error {
-	var err error
+	return r.DoContext(ctx, func(_ context.Context, attempt int) error {
+		return f(attempt)
+	})
+}
+
+// DoContext runs the provided ContextAttemptFunc according to the retry
+// configuration, with the same semantics as Do.
+//
+// Each attempt receives a context derived from ctx. If an attempt timeout
+// is configured and the attempt fails because it expired, the failure is
+// reported as ErrAttemptTimeout, wrapping the error of the attempt, and
+// classified as any other error, while expiry of ctx itself still stops
+// retries immediately.
+func (r retrier) DoContext(ctx context.Context, f ContextAttemptFunc) error {
+	_, err := r.run(ctx, func(ctx context.Context, attempt int) (any, error) {
+		return nil, f(ctx, attempt)
+	})
+	return err
+}
+
+// run runs a Do call, or joins the in-flight one with the same dedup key,
+// and returns the value of the successful attempt.
+func (r retrier) run(ctx context.Context, f valueFunc) (any, error) {
+	if r.dedup != nil {
+		if key := r.dedupKey(ctx); key != "" {
+			return r.dedup.do(ctx, key, func() (any, error) { return r.exec(ctx, f) })
+		}
+	}
+	return r.exec(ctx, f)
+}
+
+// exec runs a Do call and returns the value of the successful attempt.
+func (r retrier) exec(ctx context.Context, f valueFunc) (any, error) {
+	var v any
+	err := r.call(ctx, func(ctx context.Context, attempt int) error {
+		var err error
+		v, err = f(ctx, attempt)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// call runs a single Do call.
+func (r retrier) call(ctx context.Context, f ContextAttemptFunc) error {
+	run := r.life.current()
+	if !run.enter() {
+		return ErrShutdown
+	}
+	defer run.leave()
+
+	if r.bulkhead != nil {
+		if err := r.bulkhead.acquire(ctx); err != nil {
+			return err
+		}
+		defer r.bulkhead.release()
+	}
+
+	if b := r.callBudget(ctx); b != nil {
+		b.recordCall()
+	}
+
+	ctx = r.nameContext(ctx)
+	ctx = r.startRecordings(ctx)
+	ctx = r.sample(ctx)
+	ctx, id := r.correlate(ctx)
+	ctx, endTask := r.traceTask(ctx)
+	defer endTask()
+	r.trackInFlight(1)
+	defer r.trackInFlight(-1)
+
+	var trace *attemptTrace
+	if r.attemptTrace {
+		trace = &attemptTrace{}
+	}
+	attempts, err := r.do(ctx, r.wrap(r.recoverAttempt(f)), trace, run.stopped())
+	err = trace.wrap(err)
+	if id != "" && err != nil {
+		err = &correlatedError{err: err, id: id}
+	}
+	for _, h := range r.hooks {
+		h.finished(ctx, attempts, err)
+	}
+	return err
+}
+
+// do runs the retry loop and returns the number of attempts made along
+// with the error to return to the caller. Failed attempts are recorded
+// into trace if it is not nil.
+func (r retrier) do(ctx context.Context, f ContextAttemptFunc, trace *attemptTrace, stop <-chan struct{}) (int, error) {
+	var (
+		err     error
+		errs    []error
+		attempt int
+		delay   time.Duration
+		start   = time.Now()
+		release func()
+	)
+
+	for ; r.maxAttempts == 0 || attempt < r.maxAttempts; attempt++ {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return attempt, ctxErr
+		}
+		select {
+		case <-stop:
+			return attempt, shutdownError(err)
+		default:
+		}
+		if waitErr := r.awaitActive(ctx, stop); waitErr != nil {
+			return attempt, waitError(waitErr, err)
+		}
+		if waitErr := r.awaitHealthy(ctx, attempt, stop); waitErr != nil {
+			return attempt, waitError(waitErr, err)
+		}
+		if r.limiter != nil {
+			if err := r.limiter.Wait(ctx); err != nil {
+				return attempt, err
+			}
+		}
+
+		for _, h := range r.hooks {
+			h.attemptStarted(ctx, attempt)
+		}
+		attemptStart := time.Now()
+		err = r.runAttempt(ctx, f, Attempt{Number: attempt, Delay: delay})
+		for _, h := range r.hooks {
+			h.attemptFinished(ctx, attempt, err, time.Since(attemptStart))
+		}
+
+		if err == nil {
+			return attempt + 1, nil
+		}
+		trace.failed(attempt, attemptStart, err)
+		if r.aggregateErrors {
+			errs = append(errs, err)
+		}
 
-	for attempt := 0; r.maxAttempts == 0 || attempt < r.maxAttempts; attempt++ {
 		if ctxErr := ctx.Err(); ctxErr != nil {
-			return ctxErr
+			return attempt + 1, ctxErr
+		}
+
+		retryable, multiplier := r.classify(err)
+		if !retryable {
+			if IsUnretryable(err) {
+				return attempt + 1, err
+			}
+			return attempt + 1, newUnretryableError(err)
+		}
+		if r.maxAttempts > 0 && attempt+1 >= r.maxAttempts {
+			break
+		}
+		if release == nil {
+			var slotErr error
+			if release, slotErr = acquireRetrySlot(ctx); slotErr != nil {
+				if slotErr == errRetryShed {
+					return attempt + 1, &BudgetExceededError{err: err}
+				}
+				return attempt + 1, slotErr
+			}
+			defer release()
+		}
+		pressure := r.pressure()
+		if shedByPressure(pressure) || r.shedByPriority(ctx, pressure) || !r.spendRetry(ctx, attempt+1) {
+			return attempt + 1, &BudgetExceededError{err: err}
 		}
 
-		if err = f(attempt); err == nil {
-			return nil
+		delay = scaleDelay(r.backoff.Next(attempt), multiplier*pressureScale(pressure))
+		if hint, ok := r.hintedDelay(err); ok {
+			delay = hint
 		}
+		trace.sleeping(delay)
 
-		if r.isRetryable != nil && !r.isRetryable(err) {
-			return newUnretryableError(err)
+		for _, h := range r.hooks {
+			h.retrying(ctx, attempt, err, delay)
 		}
 
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case <-time.After(r.backoff.Next(attempt)):
+		if waitErr := r.sleep(ctx, attempt, delay, stop); waitErr != nil {
+			return attempt + 1, waitError(waitErr, err)
 		}
 	}
 
-	return fmt.Errorf("all attempts failed: %w", err)
+	if r.aggregateErrors {
+		err = newAggregateError(errs)
+	}
+	return attempt + 1, &MaxAttemptsError{Attempts: attempt + 1, Elapsed: time.Since(start), err: err}
+}
+
+// sleep waits for delay after attempt failed, or until ctx is done or stop
+// is closed, in which case it returns ErrShutdown.
+func (r retrier) sleep(ctx context.Context, attempt int, delay time.Duration, stop <-chan struct{}) error {
+	defer r.traceRegion(ctx, TraceBackoffRegion, attempt)()
+	r.trackSleeping(1)
+	defer r.trackSleeping(-1)
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-stop:
+		return ErrShutdown
+	case <-timer.C:
+		return nil
+	}
+}
+
+// runAttempt executes a single attempt, applying the attempt timeout if set.
+// A deadline error caused by the attempt timeout rather than by ctx is
+// marked with ErrAttemptTimeout.
+func (r retrier) runAttempt(ctx context.Context, f ContextAttemptFunc, attempt Attempt) error {
+	defer r.traceRegion(ctx, TraceAttemptRegion, attempt.Number)()
+
+	actx := context.WithValue(ctx, attemptKey{}, attempt)
+	if r.attemptTimeout <= 0 {
+		return f(actx, attempt.Number)
+	}
+
+	actx, cancel := context.WithTimeout(actx, r.attemptTimeout)
+	defer cancel()
+	defer r.watchLeak(attempt.Number)()
+
+	err := f(actx, attempt.Number)
+	if err != nil && errors.Is(err, context.DeadlineExceeded) &&
+		ctx.Err() == nil && actx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("%w: %w", ErrAttemptTimeout, err)
+	}
+	return err
 }
 
 // defaultAttempts returns the default maximum number of retry attempts.
@@ -137,5 +466,56 @@ func WithBackoff(backoff Backoff) RetryOption {
 func WithIsRetryableFunc(isRetryable IsRetryableFunc) RetryOption {
 	return func(r *retrier) {
 		r.isRetryable = isRetryable
+		r.retryableDesc = "custom retry check"
+	}
+}
+
+// WithRetryableErrors retries only errors that match one of errs
+// according to errors.Is. Any other error stops retries immediately.
+func WithRetryableErrors(errs ...error) RetryOption {
+	return func(r *retrier) {
+		r.isRetryable = func(err error) bool {
+			return isAny(err, errs)
+		}
+		r.retryableDesc = "retry on: " + errorList(errs)
+	}
+}
+
+// WithUnretryableErrors retries every error except those that match one
+// of errs according to errors.Is, which stop retries immediately.
+func WithUnretryableErrors(errs ...error) RetryOption {
+	return func(r *retrier) {
+		r.isRetryable = func(err error) bool {
+			return !isAny(err, errs)
+		}
+		r.retryableDesc = "stop on: " + errorList(errs)
+	}
+}
+
+// WithAttemptTimeout bounds the duration of each attempt run through
+// DoContext. A value of 0 means attempts are only bounded by the parent
+// context.
+func WithAttemptTimeout(d time.Duration) RetryOption {
+	return func(r *retrier) {
+		r.attemptTimeout = d
+	}
+}
+
+// WithErrorAggregation makes the error returned after all attempts failed
+// carry the errors of every attempt instead of only the last one.
+// Runs of consecutive identical errors are collapsed in the message,
+// e.g. "connection refused (x7)"; every error remains reachable through
+// errors.Is and errors.As.
+func WithErrorAggregation() RetryOption {
+	return func(r *retrier) {
+		r.aggregateErrors = true
+	}
+}
+
+// WithOnRetry registers fn to be called before every retry.
+// Multiple functions are called in registration order.
+func WithOnRetry(fn OnRetryFunc) RetryOption {
+	return func(r *retrier) {
+		r.onRetry = append(r.onRetry, fn)
 	}
 }
@@ -3,6 +3,7 @@ package retry
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -58,6 +59,23 @@ func TestRetrier_Do(t *testing.T) {
 			wantErrMsg: "all attempts failed: always fail",
 			wantCalls:  3,
 		},
+		{
+			name: "all attempts failed aggregated",
+			opts: []RetryOption{
+				WithMaxAttempts(4),
+				WithBackoff(FixedBackoff{Interval: time.Millisecond}),
+				WithErrorAggregation(),
+			},
+			fn: func(attempt int) error {
+				if attempt == 3 {
+					return errCustom
+				}
+				return errAlwaysFail
+			},
+			ctx:        context.Background,
+			wantErrMsg: "all attempts failed: always fail (x3)\ncustom error",
+			wantCalls:  4,
+		},
 		{
 			name: "non-retryable error",
 			opts: []RetryOption{
@@ -71,6 +89,40 @@ func TestRetrier_Do(t *testing.T) {
 			wantErrMsg: "unretryable error: custom error",
 			wantCalls:  1,
 		},
+		{
+			name: "retryable errors whitelist",
+			opts: []RetryOption{
+				WithMaxAttempts(5),
+				WithBackoff(FixedBackoff{Interval: time.Millisecond}),
+				WithRetryableErrors(errAlwaysFail),
+			},
+			fn: func(attempt int) error {
+				if attempt < 2 {
+					return fmt.Errorf("wrapped: %w", errAlwaysFail)
+				}
+				return errCustom
+			},
+			ctx:        context.Background,
+			wantErrMsg: "unretryable error: custom error",
+			wantCalls:  3,
+		},
+		{
+			name: "unretryable errors blacklist",
+			opts: []RetryOption{
+				WithMaxAttempts(5),
+				WithBackoff(FixedBackoff{Interval: time.Millisecond}),
+				WithUnretryableErrors(errCustom),
+			},
+			fn: func(attempt int) error {
+				if attempt < 1 {
+					return errAlwaysFail
+				}
+				return fmt.Errorf("wrapped: %w", errCustom)
+			},
+			ctx:        context.Background,
+			wantErrMsg: "unretryable error: wrapped: custom error",
+			wantCalls:  2,
+		},
 		{
 			name: "context canceled",
 			opts: []RetryOption{WithMaxAttempts(5), WithBackoff(FixedBackoff{Interval: time.Millisecond})},
@@ -91,7 +143,8 @@ func TestRetrier_Do(t *testing.T) {
 				return errAlwaysFail
 			},
 			ctx: func() context.Context {
-				ctx, _ := context.WithTimeout(context.Background(), 50*time.Millisecond)
+				ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+				time.AfterFunc(time.Second, cancel)
 				return ctx
 			},
 			wantErr: context.DeadlineExceeded,
@@ -138,3 +191,108 @@ func TestRetrier_Do(t *testing.T) {
 		})
 	}
 }
+
+func TestRetrier_DoContextAttemptTimeout(t *testing.T) {
+	t.Run("attempt deadline is retried", func(t *testing.T) {
+		r := New(
+			WithMaxAttempts(3),
+			WithBackoff(FixedBackoff{Interval: time.Millisecond}),
+			WithAttemptTimeout(5*time.Millisecond),
+		)
+
+		calls := 0
+		err := r.DoContext(context.Background(), func(ctx context.Context, attempt int) error {
+			calls++
+			if attempt < 2 {
+				<-ctx.Done()
+				return ctx.Err()
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("classifier stops attempt timeouts", func(t *testing.T) {
+		r := New(
+			WithMaxAttempts(3),
+			WithBackoff(FixedBackoff{Interval: time.Millisecond}),
+			WithAttemptTimeout(time.Millisecond),
+			WithIsRetryableFunc(func(err error) bool { return !errors.Is(err, ErrAttemptTimeout) }),
+		)
+
+		calls := 0
+		err := r.DoContext(context.Background(), func(ctx context.Context, attempt int) error {
+			calls++
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		assert.True(t, IsUnretryable(err))
+		assert.ErrorIs(t, err, ErrAttemptTimeout)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("all attempts timed out", func(t *testing.T) {
+		r := New(
+			WithMaxAttempts(2),
+			WithBackoff(FixedBackoff{Interval: time.Millisecond}),
+			WithAttemptTimeout(time.Millisecond),
+		)
+
+		err := r.DoContext(context.Background(), func(ctx context.Context, attempt int) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		assert.ErrorIs(t, err, ErrAttemptTimeout)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("parent deadline stops immediately", func(t *testing.T) {
+		r := New(
+			WithMaxAttempts(10),
+			WithBackoff(FixedBackoff{Interval: time.Millisecond}),
+			WithAttemptTimeout(time.Second),
+		)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		calls := 0
+		err := r.DoContext(ctx, func(ctx context.Context, attempt int) error {
+			calls++
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		assert.Equal(t, context.DeadlineExceeded, err)
+		assert.Equal(t, 1, calls)
+	})
+}
+
+func TestWithOnRetry(t *testing.T) {
+	var got []int
+	r := New(
+		WithMaxAttempts(3),
+		WithBackoff(FixedBackoff{Interval: time.Millisecond}),
+		WithOnRetry(func(ctx context.Context, attempt int, err error, delay time.Duration) {
+			assert.ErrorIs(t, err, errAlwaysFail)
+			assert.Equal(t, time.Millisecond, delay)
+			got = append(got, attempt)
+		}),
+	)
+	_ = r.Do(context.Background(), func(attempt int) error { return errAlwaysFail })
+	assert.Equal(t, []int{0, 1}, got)
+}
+
+func TestAsContextRetrier(t *testing.T) {
+	r := New()
+	assert.Equal(t, r, AsContextRetrier(r), "context retriers are returned as is")
+	assert.Nil(t, AsContextRetrier(nil))
+
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "call")
+	err := AsContextRetrier(doOnlyRetrier{}).DoContext(ctx, func(ctx context.Context, attempt int) error {
+		assert.Equal(t, "call", ctx.Value(ctxKey{}))
+		return errAlwaysFail
+	})
+	assert.ErrorIs(t, err, errAlwaysFail)
+}
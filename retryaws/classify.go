@@ -0,0 +1,83 @@
+// Package retryaws integrates the retry package with the AWS SDK for Go v2.
+//
+// Errors are recognized through the small interfaces the SDK's error types
+// implement (smithy.APIError and the HTTP response errors), so the SDK does
+// not need to be imported.
+package retryaws
+
+import (
+	"errors"
+	"slices"
+)
+
+// apiError matches smithy.APIError.
+type apiError interface {
+	error
+	ErrorCode() string
+}
+
+// httpStatusError matches awshttp.ResponseError and smithyhttp.ResponseError.
+type httpStatusError interface {
+	error
+	HTTPStatusCode() int
+}
+
+// IsRetryable reports whether err is an AWS error worth retrying:
+// a throttling error (see IsThrottle), a request timeout, or a
+// 5xx service error.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	if IsThrottle(err) {
+		return true
+	}
+
+	var ae apiError
+	if errors.As(err, &ae) && slices.Contains(transientCodes(), ae.ErrorCode()) {
+		return true
+	}
+
+	var he httpStatusError
+	return errors.As(err, &he) && slices.Contains(transientStatusCodes(), he.HTTPStatusCode())
+}
+
+// IsThrottle reports whether err is an AWS throttling error, identified by
+// the same error codes the SDK's own retryer treats as throttles.
+func IsThrottle(err error) bool {
+	var ae apiError
+	return errors.As(err, &ae) && slices.Contains(throttleCodes(), ae.ErrorCode())
+}
+
+// throttleCodes returns the error codes AWS services use for throttling.
+func throttleCodes() []string {
+	return []string{
+		"Throttling",
+		"ThrottlingException",
+		"ThrottledException",
+		"RequestThrottledException",
+		"TooManyRequestsException",
+		"ProvisionedThroughputExceededException",
+		"TransactionInProgressException",
+		"RequestLimitExceeded",
+		"BandwidthLimitExceeded",
+		"LimitExceededException",
+		"RequestThrottled",
+		"SlowDown",
+		"PriorRequestNotComplete",
+		"EC2ThrottledException",
+	}
+}
+
+// transientCodes returns the non-throttling error codes worth retrying.
+func transientCodes() []string {
+	return []string{
+		"RequestTimeout",
+		"RequestTimeoutException",
+	}
+}
+
+// transientStatusCodes returns the HTTP status codes worth retrying.
+func transientStatusCodes() []int {
+	return []int{500, 502, 503, 504}
+}
@@ -0,0 +1,59 @@
+package retryaws
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// genericAPIError mirrors smithy.GenericAPIError.
+type genericAPIError struct{ code string }
+
+func (e *genericAPIError) Error() string     { return "api error " + e.code }
+func (e *genericAPIError) ErrorCode() string { return e.code }
+
+// responseError mirrors awshttp.ResponseError, which wraps the API error.
+type responseError struct {
+	status int
+	err    error
+}
+
+func (e *responseError) Error() string {
+	return fmt.Sprintf("https response error StatusCode: %d, %v", e.status, e.err)
+}
+func (e *responseError) Unwrap() error       { return e.err }
+func (e *responseError) HTTPStatusCode() int { return e.status }
+
+func operationError(status int, code string) error {
+	return fmt.Errorf("operation error S3: GetObject, %w", &responseError{
+		status: status,
+		err:    &genericAPIError{code: code},
+	})
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		retryable bool
+		throttle  bool
+	}{
+		{"nil", nil, false, false},
+		{"slow down", operationError(503, "SlowDown"), true, true},
+		{"dynamodb throughput", operationError(400, "ProvisionedThroughputExceededException"), true, true},
+		{"request timeout", operationError(400, "RequestTimeout"), true, false},
+		{"internal error", operationError(500, "InternalError"), true, false},
+		{"access denied", operationError(403, "AccessDenied"), false, false},
+		{"no such key", operationError(404, "NoSuchKey"), false, false},
+		{"plain", errors.New("boom"), false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.retryable, IsRetryable(tt.err))
+			assert.Equal(t, tt.throttle, IsThrottle(tt.err))
+		})
+	}
+}
@@ -0,0 +1,132 @@
+// Package retrygrpc integrates the retry package with gRPC clients.
+package retrygrpc
+
+import (
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/er-davo/retry"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Option configures a gRPC classifier.
+type Option func(*classifier)
+
+type classifier struct {
+	codes    []codes.Code
+	messages []*regexp.Regexp
+}
+
+// IsRetryable reports whether err is a gRPC status error worth retrying
+// using the default classifier configuration.
+func IsRetryable(err error) bool {
+	return Classifier()(err)
+}
+
+// Classifier returns a retry.IsRetryableFunc for gRPC status errors.
+//
+// A status is retried when:
+//   - it carries a RetryInfo detail, since the server explicitly asked for a retry
+//   - its message matches one of the configured patterns
+//   - its code is one of the configured codes
+//
+// A status carrying a QuotaFailure detail without RetryInfo is never
+// retried: the quota will not recover within the retry window.
+// Errors that are not gRPC statuses are not retried.
+//
+// By default it retries Unavailable, ResourceExhausted and Aborted.
+func Classifier(opts ...Option) retry.IsRetryableFunc {
+	c := &classifier{
+		codes: defaultCodes(),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c.isRetryable
+}
+
+func (c *classifier) isRetryable(err error) bool {
+	st, ok := status.FromError(err)
+	if !ok || st.Code() == codes.OK {
+		return false
+	}
+
+	var quota bool
+	for _, d := range st.Details() {
+		switch d.(type) {
+		case *errdetails.RetryInfo:
+			return true
+		case *errdetails.QuotaFailure:
+			quota = true
+		}
+	}
+	if quota {
+		return false
+	}
+
+	for _, re := range c.messages {
+		if re.MatchString(st.Message()) {
+			return true
+		}
+	}
+
+	return slices.Contains(c.codes, st.Code())
+}
+
+// defaultCodes returns the status codes retried by default.
+func defaultCodes() []codes.Code {
+	return []codes.Code{
+		codes.Unavailable,
+		codes.ResourceExhausted,
+		codes.Aborted,
+	}
+}
+
+// WithCodes replaces the set of retryable status codes.
+func WithCodes(c ...codes.Code) Option {
+	return func(cl *classifier) {
+		cl.codes = c
+	}
+}
+
+// WithRetryableMessages retries statuses whose message matches one of the
+// regular expression patterns, regardless of their code.
+// It panics if a pattern cannot be compiled.
+func WithRetryableMessages(patterns ...string) Option {
+	return func(cl *classifier) {
+		for _, p := range patterns {
+			cl.messages = append(cl.messages, regexp.MustCompile(p))
+		}
+	}
+}
+
+// RetryDelay returns the delay requested by the server through a
+// RetryInfo status detail, if any.
+func RetryDelay(err error) (time.Duration, bool) {
+	st, ok := status.FromError(err)
+	if !ok {
+		return 0, false
+	}
+
+	for _, d := range st.Details() {
+		if ri, ok := d.(*errdetails.RetryInfo); ok && ri.GetRetryDelay() != nil {
+			return ri.GetRetryDelay().AsDuration(), true
+		}
+	}
+	return 0, false
+}
+
+// AnnotateDelay wraps err with the delay requested through RetryInfo so
+// the retrier waits as long as the server asked before the next attempt.
+// Errors without RetryInfo are returned unchanged.
+func AnnotateDelay(err error) error {
+	if d, ok := RetryDelay(err); ok {
+		return retry.RetryAfter(err, d)
+	}
+	return err
+}
@@ -0,0 +1,83 @@
+package retrygrpc
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func withDetails(t *testing.T, c codes.Code, msg string, details ...*errdetails.RetryInfo) error {
+	t.Helper()
+	st := status.New(c, msg)
+	for _, d := range details {
+		var err error
+		st, err = st.WithDetails(d)
+		require.NoError(t, err)
+	}
+	return st.Err()
+}
+
+func TestClassifier(t *testing.T) {
+	quota, err := status.New(codes.ResourceExhausted, "daily quota").
+		WithDetails(&errdetails.QuotaFailure{})
+	require.NoError(t, err)
+
+	retryInfo := &errdetails.RetryInfo{RetryDelay: durationpb.New(2 * time.Second)}
+
+	tests := []struct {
+		name     string
+		classify func(error) bool
+		err      error
+		want     bool
+	}{
+		{"unavailable", IsRetryable, status.Error(codes.Unavailable, "down"), true},
+		{"wrapped aborted", IsRetryable, fmt.Errorf("call: %w", status.Error(codes.Aborted, "conflict")), true},
+		{"invalid argument", IsRetryable, status.Error(codes.InvalidArgument, "bad"), false},
+		{"quota failure", IsRetryable, quota.Err(), false},
+		{"retry info overrides code", IsRetryable, withDetails(t, codes.FailedPrecondition, "busy", retryInfo), true},
+		{"custom codes", Classifier(WithCodes(codes.Internal)), status.Error(codes.Internal, "oops"), true},
+		{"custom codes exclude default", Classifier(WithCodes(codes.Internal)), status.Error(codes.Unavailable, "down"), false},
+		{"message pattern", Classifier(WithRetryableMessages(`transport is closing`)), status.Error(codes.Unknown, "transport is closing"), true},
+		{"not a status", IsRetryable, errors.New("plain"), false},
+		{"nil", IsRetryable, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.classify(tt.err))
+		})
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	err := withDetails(t, codes.Unavailable, "down",
+		&errdetails.RetryInfo{RetryDelay: durationpb.New(1500 * time.Millisecond)})
+
+	d, ok := RetryDelay(err)
+	assert.True(t, ok)
+	assert.Equal(t, 1500*time.Millisecond, d)
+
+	_, ok = RetryDelay(status.Error(codes.Unavailable, "down"))
+	assert.False(t, ok)
+
+	annotated := AnnotateDelay(err)
+	var hinter interface {
+		RetryDelay() (time.Duration, bool)
+	}
+	require.True(t, errors.As(annotated, &hinter))
+	d, ok = hinter.RetryDelay()
+	assert.True(t, ok)
+	assert.Equal(t, 1500*time.Millisecond, d)
+	assert.Equal(t, codes.Unavailable, status.Code(annotated))
+
+	plain := status.Error(codes.Unavailable, "down")
+	assert.Equal(t, plain, AnnotateDelay(plain))
+}
@@ -0,0 +1,142 @@
+package retrygrpc
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/er-davo/retry"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// PushbackTrailer is the trailer through which a server pushes back on
+// retries, as defined by the gRPC retry design: a non-negative number of
+// milliseconds to wait before the next attempt, or a negative or invalid
+// value to stop retrying.
+const PushbackTrailer = "grpc-retry-pushback-ms"
+
+// InterceptorOption configures the interceptors of the package.
+type InterceptorOption func(*interceptor)
+
+// WithMethodRetrier retries the calls to method, a full method name such
+// as "/package.Service/Method", with r instead of the retrier of the
+// interceptor.
+func WithMethodRetrier(method string, r retry.Retrier) InterceptorOption {
+	return func(i *interceptor) {
+		i.methods[method] = retry.AsContextRetrier(r)
+	}
+}
+
+type interceptor struct {
+	retrier retry.ContextRetrier
+	methods map[string]retry.ContextRetrier
+}
+
+// newInterceptor returns an interceptor retrying with r, or with the
+// default retrier of the package if r is nil.
+func newInterceptor(r retry.Retrier, opts []InterceptorOption) *interceptor {
+	i := &interceptor{retrier: retry.AsContextRetrier(r), methods: make(map[string]retry.ContextRetrier)}
+	for _, opt := range opts {
+		opt(i)
+	}
+	if i.retrier == nil {
+		i.retrier = retry.New(retry.WithIsRetryableFunc(Classifier()))
+	}
+	return i
+}
+
+// retrierFor returns the retrier of a call to method, and the call options
+// without the ones of the package.
+func (i *interceptor) retrierFor(method string, opts []grpc.CallOption) (retry.ContextRetrier, []grpc.CallOption) {
+	r, ok := i.methods[method]
+	if !ok {
+		r = i.retrier
+	}
+
+	rest := opts[:0:0]
+	for _, opt := range opts {
+		if o, ok := opt.(retrierCallOption); ok {
+			r = o.retrier
+			continue
+		}
+		rest = append(rest, opt)
+	}
+	return r, rest
+}
+
+// retrierCallOption is the grpc.CallOption of WithCallRetrier.
+type retrierCallOption struct {
+	grpc.EmptyCallOption
+	retrier retry.ContextRetrier
+}
+
+// WithCallRetrier is a call option retrying the call with r instead of the
+// retrier of the interceptor.
+func WithCallRetrier(r retry.Retrier) grpc.CallOption {
+	return retrierCallOption{retrier: retry.AsContextRetrier(r)}
+}
+
+// WithoutRetry is a call option disabling the retries of the call.
+func WithoutRetry() grpc.CallOption {
+	return WithCallRetrier(retry.NoRetry())
+}
+
+// Pushback returns the server pushback of trailer md, see PushbackTrailer:
+// ok is false without one, otherwise stop reports whether the server asked
+// to stop retrying, or else d is the delay it asked for.
+func Pushback(md metadata.MD) (d time.Duration, stop, ok bool) {
+	v := md.Get(PushbackTrailer)
+	if len(v) == 0 {
+		return 0, false, false
+	}
+	ms, err := strconv.ParseInt(v[0], 10, 64)
+	if err != nil || ms < 0 {
+		return 0, true, true
+	}
+	return time.Duration(ms) * time.Millisecond, false, true
+}
+
+// call runs attempt with r, honoring the server pushback of the trailer
+// of every failed attempt, or else the delay of its RetryInfo. A pushback
+// asking to stop retrying marks the error of the attempt unretryable.
+func call(ctx context.Context, r retry.ContextRetrier, attempt func(ctx context.Context, attempt int) (metadata.MD, error)) error {
+	return r.DoContext(ctx, func(ctx context.Context, n int) error {
+		trailer, err := attempt(ctx, n)
+		if err == nil {
+			return nil
+		}
+		d, stop, ok := Pushback(trailer)
+		switch {
+		case !ok:
+			return AnnotateDelay(err)
+		case stop:
+			return retry.Unretryable(err)
+		}
+		return retry.RetryAfter(err, d)
+	})
+}
+
+// UnaryClientInterceptor returns a gRPC interceptor retrying unary calls
+// with r. A nil r retries with the default attempts and backoff the errors
+// of Classifier. The delay a server asks for through PushbackTrailer or
+// RetryInfo is honored, and a pushback asking to stop retrying returns
+// the error at once.
+//
+// The retrier of a method can be overridden with WithMethodRetrier, and
+// the one of a call with the WithCallRetrier and WithoutRetry call options.
+// Attempts get their own context only from retriers implementing
+// retry.ContextRetrier, such as the ones of retry.New.
+func UnaryClientInterceptor(r retry.Retrier, opts ...InterceptorOption) grpc.UnaryClientInterceptor {
+	i := newInterceptor(r, opts)
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		r, opts := i.retrierFor(method, opts)
+		var trailer metadata.MD
+		opts = append(opts, grpc.Trailer(&trailer))
+		return call(ctx, r, func(ctx context.Context, _ int) (metadata.MD, error) {
+			trailer = nil
+			err := invoker(ctx, method, req, reply, cc, opts...)
+			return trailer, err
+		})
+	}
+}
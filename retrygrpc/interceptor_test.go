@@ -0,0 +1,139 @@
+package retrygrpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/er-davo/retry"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// sentinelCallOption is a call option of another package.
+type sentinelCallOption struct {
+	grpc.EmptyCallOption
+}
+
+// failingInvoker fails every call with code, counting the calls and
+// checking they only get the call options of other packages.
+func failingInvoker(t *testing.T, calls *int, code codes.Code) grpc.UnaryInvoker {
+	return func(_ context.Context, _ string, _, _ any, _ *grpc.ClientConn, opts ...grpc.CallOption) error {
+		*calls++
+		for _, opt := range opts {
+			_, ok := opt.(retrierCallOption)
+			assert.False(t, ok, "the options of the package are not passed on")
+		}
+		return status.Error(code, "failed")
+	}
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	fast := retry.New(
+		retry.WithMaxAttempts(3),
+		retry.WithBackoff(retry.FixedBackoff{Interval: time.Millisecond}),
+		retry.WithIsRetryableFunc(Classifier()),
+	)
+	single := retry.New(retry.WithMaxAttempts(2), retry.WithBackoff(retry.FixedBackoff{}))
+
+	tests := []struct {
+		name   string
+		method string
+		code   codes.Code
+		opts   []grpc.CallOption
+		calls  int
+	}{
+		{name: "retryable", method: "/svc/Get", code: codes.Unavailable, calls: 3},
+		{name: "not retryable", method: "/svc/Get", code: codes.InvalidArgument, calls: 1},
+		{name: "method retrier", method: "/svc/Put", code: codes.Unavailable, calls: 2},
+		{
+			name: "call retrier", method: "/svc/Get", code: codes.Unavailable, calls: 2,
+			opts: []grpc.CallOption{sentinelCallOption{}, WithCallRetrier(single)},
+		},
+		{
+			name: "without retry", method: "/svc/Put", code: codes.Unavailable, calls: 1,
+			opts: []grpc.CallOption{WithoutRetry(), sentinelCallOption{}},
+		},
+	}
+
+	interceptor := UnaryClientInterceptor(fast, WithMethodRetrier("/svc/Put", single))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			err := interceptor(context.Background(), tt.method, nil, nil, nil, failingInvoker(t, &calls, tt.code), tt.opts...)
+			assert.Equal(t, tt.code, status.Code(err))
+			assert.Equal(t, tt.calls, calls)
+		})
+	}
+}
+
+func TestUnaryClientInterceptor_Default(t *testing.T) {
+	calls := 0
+	invoker := func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+		calls++
+		if calls == 1 {
+			return status.Error(codes.Aborted, "conflict")
+		}
+		return nil
+	}
+
+	err := UnaryClientInterceptor(nil)(context.Background(), "/svc/Get", nil, nil, nil, invoker)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls)
+}
+
+func TestPushback(t *testing.T) {
+	tests := []struct {
+		name  string
+		md    metadata.MD
+		delay time.Duration
+		stop  bool
+		ok    bool
+	}{
+		{name: "none", md: nil},
+		{name: "delay", md: metadata.Pairs(PushbackTrailer, "250"), delay: 250 * time.Millisecond, ok: true},
+		{name: "negative", md: metadata.Pairs(PushbackTrailer, "-1"), stop: true, ok: true},
+		{name: "invalid", md: metadata.Pairs(PushbackTrailer, "soon"), stop: true, ok: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, stop, ok := Pushback(tt.md)
+			assert.Equal(t, tt.delay, d)
+			assert.Equal(t, tt.stop, stop)
+			assert.Equal(t, tt.ok, ok)
+		})
+	}
+}
+
+func TestUnaryClientInterceptor_Pushback(t *testing.T) {
+	var delays []time.Duration
+	r := retry.New(
+		retry.WithMaxAttempts(3),
+		retry.WithBackoff(retry.FixedBackoff{Interval: time.Hour}),
+		retry.WithOnRetry(func(_ context.Context, _ int, _ error, d time.Duration) {
+			delays = append(delays, d)
+		}),
+	)
+
+	pushbacks := []string{"1", "-1"}
+	calls := 0
+	invoker := func(_ context.Context, _ string, _, _ any, _ *grpc.ClientConn, opts ...grpc.CallOption) error {
+		for _, opt := range opts {
+			if o, ok := opt.(grpc.TrailerCallOption); ok {
+				*o.TrailerAddr = metadata.Pairs(PushbackTrailer, pushbacks[calls])
+			}
+		}
+		calls++
+		return status.Error(codes.Unavailable, "overloaded")
+	}
+
+	err := UnaryClientInterceptor(r)(context.Background(), "/svc/Get", nil, nil, nil, invoker)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Equal(t, 2, calls, "a negative pushback stops the retries")
+	assert.True(t, retry.IsUnretryable(err))
+	assert.Equal(t, []time.Duration{time.Millisecond}, delays, "the pushback overrides the backoff")
+}
@@ -0,0 +1,125 @@
+package retrygrpc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/er-davo/retry"
+	"google.golang.org/grpc/codes"
+)
+
+// maxPolicyAttempts is the limit gRPC puts on the maxAttempts of a retry
+// policy: larger values are treated as it.
+const maxPolicyAttempts = 5
+
+// RetryPolicy is the retryPolicy of a method in a gRPC service config, see
+// https://github.com/grpc/proposal/blob/master/A6-client-retries.md.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first.
+	MaxAttempts int
+	// InitialBackoff is the upper bound of the delay before the first retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the upper bound of the delays.
+	MaxBackoff time.Duration
+	// BackoffMultiplier multiplies the upper bound after every retry.
+	BackoffMultiplier float64
+	// RetryableStatusCodes are the status codes retried.
+	RetryableStatusCodes []codes.Code
+}
+
+// ParseRetryPolicy parses the retryPolicy JSON object of a gRPC service
+// config, such as:
+//
+//	{
+//	  "maxAttempts": 4,
+//	  "initialBackoff": "0.1s",
+//	  "maxBackoff": "1s",
+//	  "backoffMultiplier": 2,
+//	  "retryableStatusCodes": ["UNAVAILABLE"]
+//	}
+//
+// It validates the policy as gRPC does, treating maxAttempts above 5 as 5.
+func ParseRetryPolicy(data []byte) (RetryPolicy, error) {
+	var raw struct {
+		MaxAttempts          int          `json:"maxAttempts"`
+		InitialBackoff       string       `json:"initialBackoff"`
+		MaxBackoff           string       `json:"maxBackoff"`
+		BackoffMultiplier    float64      `json:"backoffMultiplier"`
+		RetryableStatusCodes []codes.Code `json:"retryableStatusCodes"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return RetryPolicy{}, err
+	}
+
+	p := RetryPolicy{
+		MaxAttempts:          min(raw.MaxAttempts, maxPolicyAttempts),
+		BackoffMultiplier:    raw.BackoffMultiplier,
+		RetryableStatusCodes: raw.RetryableStatusCodes,
+	}
+	var err error
+	if p.InitialBackoff, err = parseDuration(raw.InitialBackoff); err != nil {
+		return RetryPolicy{}, fmt.Errorf("initialBackoff: %w", err)
+	}
+	if p.MaxBackoff, err = parseDuration(raw.MaxBackoff); err != nil {
+		return RetryPolicy{}, fmt.Errorf("maxBackoff: %w", err)
+	}
+
+	switch {
+	case p.MaxAttempts < 2:
+		return RetryPolicy{}, errors.New("maxAttempts must be greater than 1")
+	case p.InitialBackoff <= 0:
+		return RetryPolicy{}, errors.New("initialBackoff must be positive")
+	case p.MaxBackoff <= 0:
+		return RetryPolicy{}, errors.New("maxBackoff must be positive")
+	case p.BackoffMultiplier <= 0:
+		return RetryPolicy{}, errors.New("backoffMultiplier must be positive")
+	case len(p.RetryableStatusCodes) == 0:
+		return RetryPolicy{}, errors.New("retryableStatusCodes must not be empty")
+	}
+	return p, nil
+}
+
+// parseDuration parses a duration in the JSON format of
+// google.protobuf.Duration, e.g. "1.5s".
+func parseDuration(s string) (time.Duration, error) {
+	secs, ok := strings.CutSuffix(s, "s")
+	if !ok {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	f, err := strconv.ParseFloat(secs, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return time.Duration(f * float64(time.Second)), nil
+}
+
+// Backoff returns the backoff of the policy: as gRPC does, every delay is
+// drawn uniformly between 0 and its upper bound, InitialBackoff times
+// BackoffMultiplier for every previous retry, capped by MaxBackoff.
+func (p RetryPolicy) Backoff() retry.Backoff {
+	return policyBackoff(p)
+}
+
+// Retrier returns a Retrier applying the policy, configured further by
+// opts.
+func (p RetryPolicy) Retrier(opts ...retry.RetryOption) retry.ContextRetrier {
+	return retry.New(append([]retry.RetryOption{
+		retry.WithMaxAttempts(p.MaxAttempts),
+		retry.WithBackoff(p.Backoff()),
+		retry.WithIsRetryableFunc(Classifier(WithCodes(p.RetryableStatusCodes...))),
+	}, opts...)...)
+}
+
+// policyBackoff is the backoff of a RetryPolicy.
+type policyBackoff RetryPolicy
+
+func (b policyBackoff) Next(attempt int) time.Duration {
+	bound := math.Min(float64(b.InitialBackoff)*math.Pow(b.BackoffMultiplier, float64(attempt)), float64(b.MaxBackoff))
+	return time.Duration(rand.Float64() * bound)
+}
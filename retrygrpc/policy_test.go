@@ -0,0 +1,88 @@
+package retrygrpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestParseRetryPolicy(t *testing.T) {
+	p, err := ParseRetryPolicy([]byte(`{
+		"maxAttempts": 7,
+		"initialBackoff": "0.1s",
+		"maxBackoff": "1.5s",
+		"backoffMultiplier": 2,
+		"retryableStatusCodes": ["UNAVAILABLE", "RESOURCE_EXHAUSTED", 10]
+	}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, RetryPolicy{
+		MaxAttempts:          5,
+		InitialBackoff:       100 * time.Millisecond,
+		MaxBackoff:           1500 * time.Millisecond,
+		BackoffMultiplier:    2,
+		RetryableStatusCodes: []codes.Code{codes.Unavailable, codes.ResourceExhausted, codes.Aborted},
+	}, p, "maxAttempts is capped to 5")
+}
+
+func TestParseRetryPolicy_Invalid(t *testing.T) {
+	valid := `"initialBackoff": "1s", "maxBackoff": "1s", "backoffMultiplier": 2, "retryableStatusCodes": ["UNAVAILABLE"]`
+	tests := []struct {
+		name string
+		json string
+	}{
+		{name: "syntax", json: `{`},
+		{name: "max attempts", json: `{"maxAttempts": 1, ` + valid + `}`},
+		{name: "duration unit", json: `{"maxAttempts": 2, "initialBackoff": "100ms", "maxBackoff": "1s", "backoffMultiplier": 2, "retryableStatusCodes": ["UNAVAILABLE"]}`},
+		{name: "missing backoff", json: `{"maxAttempts": 2, "maxBackoff": "1s", "backoffMultiplier": 2, "retryableStatusCodes": ["UNAVAILABLE"]}`},
+		{name: "multiplier", json: `{"maxAttempts": 2, "initialBackoff": "1s", "maxBackoff": "1s", "retryableStatusCodes": ["UNAVAILABLE"]}`},
+		{name: "codes", json: `{"maxAttempts": 2, "initialBackoff": "1s", "maxBackoff": "1s", "backoffMultiplier": 2}`},
+		{name: "unknown code", json: `{"maxAttempts": 2, "initialBackoff": "1s", "maxBackoff": "1s", "backoffMultiplier": 2, "retryableStatusCodes": ["BUSY"]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseRetryPolicy([]byte(tt.json))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	b := RetryPolicy{InitialBackoff: time.Second, MaxBackoff: 3 * time.Second, BackoffMultiplier: 2}.Backoff()
+	for range 100 {
+		assert.LessOrEqual(t, b.Next(0), time.Second)
+		assert.LessOrEqual(t, b.Next(1), 2*time.Second)
+		assert.LessOrEqual(t, b.Next(5), 3*time.Second, "the delays are capped")
+	}
+}
+
+func TestRetryPolicy_Retrier(t *testing.T) {
+	p := RetryPolicy{
+		MaxAttempts:          3,
+		InitialBackoff:       time.Millisecond,
+		MaxBackoff:           time.Millisecond,
+		BackoffMultiplier:    1,
+		RetryableStatusCodes: []codes.Code{codes.Unavailable},
+	}
+	r := p.Retrier()
+
+	calls := 0
+	_ = r.DoContext(context.Background(), func(context.Context, int) error {
+		calls++
+		return status.Error(codes.Unavailable, "down")
+	})
+	assert.Equal(t, 3, calls)
+
+	calls = 0
+	_ = r.DoContext(context.Background(), func(context.Context, int) error {
+		calls++
+		return status.Error(codes.Aborted, "conflict")
+	})
+	assert.Equal(t, 1, calls, "only the codes of the policy are retried")
+}
@@ -0,0 +1,140 @@
+package retrygrpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/er-davo/retry"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// ResubscribeFunc restores the state of a re-established stream for the
+// call to method, e.g. by sending again the subscription request, from
+// the last offset the caller received, before it reads the new stream.
+type ResubscribeFunc func(ctx context.Context, method string, stream grpc.ClientStream) error
+
+// StreamClientInterceptor returns a gRPC interceptor re-establishing
+// broken client streams with r. A nil r retries with the default attempts
+// and backoff the errors of Classifier.
+//
+// When receiving from a stream fails, the failure is the first attempt of
+// r: if r retries it, after its backoff, a new stream is opened and passed
+// to resubscribe, which may be nil, then receiving goes on from the new
+// stream. The caller only sees the error once r gives up. Opening the
+// stream is retried the same way. Server pushback is honored as by
+// UnaryClientInterceptor. Since the messages sent on the broken
+// stream are lost, resubscribe must replay what the server needs.
+//
+// The retrier of a method can be overridden with WithMethodRetrier, and
+// the one of a call with the WithCallRetrier and WithoutRetry call options.
+func StreamClientInterceptor(r retry.Retrier, resubscribe ResubscribeFunc, opts ...InterceptorOption) grpc.StreamClientInterceptor {
+	i := newInterceptor(r, opts)
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		r, opts := i.retrierFor(method, opts)
+		s := &stream{
+			ctx:         ctx,
+			retrier:     r,
+			resubscribe: resubscribe,
+			open: func(ctx context.Context) (grpc.ClientStream, error) {
+				return streamer(ctx, desc, cc, method, opts...)
+			},
+			method: method,
+		}
+
+		err := call(ctx, r, func(context.Context, int) (metadata.MD, error) {
+			return nil, s.connect(false)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
+	}
+}
+
+// stream is a grpc.ClientStream re-established when it breaks.
+type stream struct {
+	ctx         context.Context
+	retrier     retry.ContextRetrier
+	resubscribe ResubscribeFunc
+	open        func(context.Context) (grpc.ClientStream, error)
+	method      string
+
+	mu     sync.Mutex
+	cur    grpc.ClientStream
+	cancel context.CancelFunc
+}
+
+// connect opens a new stream, resubscribing it if asked to, and makes it
+// the current one.
+func (s *stream) connect(resubscribe bool) error {
+	ctx, cancel := context.WithCancel(s.ctx)
+	cs, err := s.open(ctx)
+	if err == nil && resubscribe && s.resubscribe != nil {
+		err = s.resubscribe(ctx, s.method, cs)
+	}
+	if err != nil {
+		cancel()
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.cur, s.cancel = cs, cancel
+	return nil
+}
+
+// current returns the current stream.
+func (s *stream) current() grpc.ClientStream {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cur
+}
+
+// RecvMsg receives a message from the current stream, re-establishing it
+// when it breaks.
+func (s *stream) RecvMsg(m any) error {
+	for {
+		broken := s.current().RecvMsg(m)
+		if broken == nil {
+			return nil
+		}
+		if errors.Is(broken, io.EOF) || s.ctx.Err() != nil {
+			s.close()
+			return broken
+		}
+
+		trailer := s.current().Trailer()
+		err := call(s.ctx, s.retrier, func(_ context.Context, attempt int) (metadata.MD, error) {
+			if attempt == 0 {
+				return trailer, broken
+			}
+			return nil, s.connect(true)
+		})
+		if err != nil {
+			s.close()
+			return err
+		}
+	}
+}
+
+// close releases the current stream.
+func (s *stream) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancel != nil {
+		s.cancel()
+		s.cancel = nil
+	}
+}
+
+func (s *stream) SendMsg(m any) error          { return s.current().SendMsg(m) }
+func (s *stream) CloseSend() error             { return s.current().CloseSend() }
+func (s *stream) Header() (metadata.MD, error) { return s.current().Header() }
+func (s *stream) Trailer() metadata.MD         { return s.current().Trailer() }
+func (s *stream) Context() context.Context     { return s.current().Context() }
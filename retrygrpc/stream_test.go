@@ -0,0 +1,135 @@
+package retrygrpc
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/er-davo/retry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// fakeStream receives msgs, then fails with err.
+type fakeStream struct {
+	grpc.ClientStream
+	msgs    []string
+	err     error
+	trailer metadata.MD
+	sent    []any
+}
+
+func (s *fakeStream) Trailer() metadata.MD { return s.trailer }
+
+func (s *fakeStream) RecvMsg(m any) error {
+	if len(s.msgs) == 0 {
+		return s.err
+	}
+	*m.(*string), s.msgs = s.msgs[0], s.msgs[1:]
+	return nil
+}
+
+func (s *fakeStream) SendMsg(m any) error {
+	s.sent = append(s.sent, m)
+	return nil
+}
+
+func TestStreamClientInterceptor(t *testing.T) {
+	streams := []*fakeStream{
+		{msgs: []string{"a", "b"}, err: status.Error(codes.Unavailable, "broken")},
+		{msgs: []string{"c"}, err: io.EOF},
+	}
+	// Reconnecting fails once.
+	results := []grpc.ClientStream{streams[0], nil, streams[1]}
+	opened := 0
+	streamer := func(context.Context, *grpc.StreamDesc, *grpc.ClientConn, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+		cs := results[opened]
+		opened++
+		if cs == nil {
+			return nil, status.Error(codes.Unavailable, "down")
+		}
+		return cs, nil
+	}
+
+	var last string
+	resubscribe := func(_ context.Context, method string, cs grpc.ClientStream) error {
+		assert.Equal(t, "/svc/Watch", method)
+		return cs.SendMsg("from " + last)
+	}
+	r := retry.New(
+		retry.WithMaxAttempts(3),
+		retry.WithBackoff(retry.FixedBackoff{Interval: time.Millisecond}),
+		retry.WithIsRetryableFunc(Classifier()),
+	)
+	interceptor := StreamClientInterceptor(r, resubscribe)
+
+	cs, err := interceptor(context.Background(), &grpc.StreamDesc{ServerStreams: true}, nil, "/svc/Watch", streamer)
+	require.NoError(t, err)
+
+	var got []string
+	for {
+		err := cs.RecvMsg(&last)
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		got = append(got, last)
+	}
+
+	assert.Equal(t, []string{"a", "b", "c"}, got)
+	assert.Equal(t, 3, opened)
+	assert.Equal(t, []any{"from b"}, streams[1].sent, "the new stream is resubscribed")
+}
+
+func TestStreamClientInterceptor_GivesUp(t *testing.T) {
+	streamer := func(context.Context, *grpc.StreamDesc, *grpc.ClientConn, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+		return &fakeStream{err: status.Error(codes.PermissionDenied, "denied")}, nil
+	}
+	interceptor := StreamClientInterceptor(nil, nil)
+
+	cs, err := interceptor(context.Background(), &grpc.StreamDesc{ServerStreams: true}, nil, "/svc/Watch", streamer)
+	require.NoError(t, err)
+
+	var msg string
+	err = cs.RecvMsg(&msg)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err), "errors that are not retryable end the stream")
+}
+
+func TestStreamClientInterceptor_Open(t *testing.T) {
+	calls := 0
+	streamer := func(context.Context, *grpc.StreamDesc, *grpc.ClientConn, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+		calls++
+		return nil, status.Error(codes.Unavailable, "down")
+	}
+	r := retry.New(retry.WithMaxAttempts(2), retry.WithBackoff(retry.FixedBackoff{}))
+	interceptor := StreamClientInterceptor(r, nil)
+
+	_, err := interceptor(context.Background(), &grpc.StreamDesc{}, nil, "/svc/Watch", streamer, WithoutRetry())
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Equal(t, 1, calls)
+}
+
+func TestStreamClientInterceptor_Pushback(t *testing.T) {
+	opened := 0
+	streamer := func(context.Context, *grpc.StreamDesc, *grpc.ClientConn, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+		opened++
+		return &fakeStream{
+			err:     status.Error(codes.Unavailable, "draining"),
+			trailer: metadata.Pairs(PushbackTrailer, "-1"),
+		}, nil
+	}
+	interceptor := StreamClientInterceptor(retry.New(retry.WithBackoff(retry.FixedBackoff{})), nil)
+
+	cs, err := interceptor(context.Background(), &grpc.StreamDesc{ServerStreams: true}, nil, "/svc/Watch", streamer)
+	require.NoError(t, err)
+
+	var msg string
+	err = cs.RecvMsg(&msg)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Equal(t, 1, opened, "the stream is not re-established")
+}
@@ -0,0 +1,163 @@
+package retryhttp
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// WithBodyBuffer makes the transport buffer in memory request bodies of up
+// to n bytes that cannot be rewound, i.e. with no GetBody, so they can be
+// retried. Larger bodies are sent once, unless WithTempFileBuffer is set.
+// NewClient buffers up to 64 KiB; a Transport does not buffer by default.
+func WithBodyBuffer(n int64) TransportOption {
+	return func(t *Transport) {
+		t.bufferLimit = n
+	}
+}
+
+// WithTempFileBuffer makes the transport stream request bodies that cannot
+// be rewound and are larger than the memory buffer, see WithBodyBuffer,
+// to a temporary file in dir, or os.TempDir if dir is empty, which every
+// attempt reads from. Bodies larger than limit bytes are sent once; a
+// non-positive limit buffers bodies of any size. The file is removed once
+// the request is done.
+func WithTempFileBuffer(dir string, limit int64) TransportOption {
+	return func(t *Transport) {
+		t.tempFile = true
+		t.tempDir = dir
+		t.tempLimit = limit
+	}
+}
+
+// buffer returns a copy of req whose body can be rewound, and a function
+// to call once the request is done. Bodies over the buffer limits are
+// streamed from what was buffered, then from the rest of req.Body, but
+// cannot be rewound.
+func (t *Transport) buffer(req *http.Request) (*http.Request, func(), error) {
+	buf, err := io.ReadAll(io.LimitReader(req.Body, t.bufferLimit+1))
+	if err != nil {
+		_ = req.Body.Close()
+		return nil, nil, err
+	}
+
+	breq := req.WithContext(req.Context())
+	if int64(len(buf)) <= t.bufferLimit {
+		_ = req.Body.Close()
+		breq.Body = io.NopCloser(bytes.NewReader(buf))
+		breq.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(buf)), nil
+		}
+		return breq, func() {}, nil
+	}
+
+	rest := io.MultiReader(bytes.NewReader(buf), req.Body)
+	if !t.tempFile {
+		breq.Body = readCloser{rest, req.Body.Close}
+		return breq, func() {}, nil
+	}
+
+	s, full, err := spoolBody(t.tempDir, rest, t.tempLimit)
+	if err != nil {
+		_ = req.Body.Close()
+		return nil, nil, err
+	}
+	body, err := s.open()
+	if err != nil {
+		s.release()
+		_ = req.Body.Close()
+		return nil, nil, err
+	}
+
+	if !full {
+		breq.Body = readCloser{io.MultiReader(body, rest), func() error {
+			_ = body.Close()
+			return req.Body.Close()
+		}}
+		return breq, s.release, nil
+	}
+
+	_ = req.Body.Close()
+	breq.Body = body
+	breq.GetBody = s.open
+	return breq, s.release, nil
+}
+
+// readCloser is an io.ReadCloser made of a reader and a close function.
+type readCloser struct {
+	io.Reader
+	close func() error
+}
+
+func (rc readCloser) Close() error { return rc.close() }
+
+// spoolBody copies r to a temporary file in dir, up to a byte past limit if
+// limit is positive. full reports whether all of r was copied.
+func spoolBody(dir string, r io.Reader, limit int64) (s *spool, full bool, err error) {
+	f, err := os.CreateTemp(dir, "retryhttp-body-*")
+	if err != nil {
+		return nil, false, err
+	}
+	src := r
+	if limit > 0 {
+		src = io.LimitReader(r, limit+1)
+	}
+	n, err := io.Copy(f, src)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return nil, false, err
+	}
+	return &spool{path: f.Name()}, limit <= 0 || n <= limit, nil
+}
+
+// spool is a request body buffered in a temporary file. The file is
+// removed once the spool is released and every reader is closed.
+type spool struct {
+	path string
+
+	mu       sync.Mutex
+	readers  int
+	released bool
+}
+
+// open returns a new reader of the body.
+func (s *spool) open() (io.ReadCloser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.Open(s.path)
+	if err != nil {
+		return nil, err
+	}
+	s.readers++
+	var once sync.Once
+	return readCloser{f, func() error {
+		err := f.Close()
+		once.Do(func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.readers--
+			s.remove()
+		})
+		return err
+	}}, nil
+}
+
+// release removes the file once its readers are closed.
+func (s *spool) release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.released = true
+	s.remove()
+}
+
+func (s *spool) remove() {
+	if s.released && s.readers == 0 {
+		_ = os.Remove(s.path)
+	}
+}
@@ -0,0 +1,75 @@
+package retryhttp
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bodyRecorder answers 503 to every request, recording their bodies.
+func bodyRecorder(bodies *[]string) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		b, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		*bodies = append(*bodies, string(b))
+		return &http.Response{StatusCode: http.StatusServiceUnavailable, Body: http.NoBody}, nil
+	})
+}
+
+func newStreamRequest(t *testing.T, body string) *http.Request {
+	req, err := http.NewRequest(http.MethodPut, "http://example.com", io.NopCloser(strings.NewReader(body)))
+	require.NoError(t, err)
+	return req
+}
+
+func TestWithBodyBuffer(t *testing.T) {
+	var bodies []string
+	rt := NewTransport(bodyRecorder(&bodies), testRetrier(2), WithBodyBuffer(5))
+
+	_, err := rt.RoundTrip(newStreamRequest(t, "12345"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"12345", "12345"}, bodies)
+
+	bodies = nil
+	_, err = rt.RoundTrip(newStreamRequest(t, "123456"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"123456"}, bodies, "larger bodies are streamed once")
+}
+
+func TestWithTempFileBuffer(t *testing.T) {
+	dir := t.TempDir()
+	var bodies []string
+	rt := NewTransport(bodyRecorder(&bodies), testRetrier(3), WithBodyBuffer(2), WithTempFileBuffer(dir, 8))
+
+	_, err := rt.RoundTrip(newStreamRequest(t, "12345678"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"12345678", "12345678", "12345678"}, bodies)
+
+	bodies = nil
+	_, err = rt.RoundTrip(newStreamRequest(t, "123456789"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"123456789"}, bodies, "larger bodies are streamed once")
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "the temporary files are removed")
+}
+
+func TestWithTempFileBuffer_NoLimit(t *testing.T) {
+	var bodies []string
+	rt := NewTransport(bodyRecorder(&bodies), testRetrier(2), WithTempFileBuffer(t.TempDir(), 0))
+
+	body := strings.Repeat("x", 1<<20)
+	_, err := rt.RoundTrip(newStreamRequest(t, body))
+	require.NoError(t, err)
+	require.Len(t, bodies, 2)
+	assert.Equal(t, body, bodies[1])
+}
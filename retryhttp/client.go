@@ -0,0 +1,117 @@
+package retryhttp
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/er-davo/retry"
+)
+
+// ErrResponseTooLarge is returned when reading a response body beyond the
+// limit set with WithMaxResponseBody.
+var ErrResponseTooLarge = errors.New("response body too large")
+
+// defaultBufferLimit is the size up to which NewClient buffers request
+// bodies that cannot be rewound.
+const defaultBufferLimit = 64 << 10
+
+// ClientOption configures NewClient.
+type ClientOption func(*clientConfig)
+
+type clientConfig struct {
+	next            http.RoundTripper
+	transport       []TransportOption
+	timeout         time.Duration
+	maxResponseBody int64
+}
+
+// WithBaseTransport sets the RoundTripper sending the requests of the
+// client. The default is http.DefaultTransport.
+func WithBaseTransport(rt http.RoundTripper) ClientOption {
+	return func(c *clientConfig) {
+		c.next = rt
+	}
+}
+
+// WithTransportOptions configures the Transport of the client.
+func WithTransportOptions(opts ...TransportOption) ClientOption {
+	return func(c *clientConfig) {
+		c.transport = append(c.transport, opts...)
+	}
+}
+
+// WithClientTimeout sets the time limit of a request, all attempts and
+// reading the response body included, as http.Client.Timeout.
+func WithClientTimeout(d time.Duration) ClientOption {
+	return func(c *clientConfig) {
+		c.timeout = d
+	}
+}
+
+// WithMaxResponseBody limits response bodies to n bytes: reading beyond
+// fails with ErrResponseTooLarge. A non-positive n disables the limit, the
+// default.
+func WithMaxResponseBody(n int64) ClientOption {
+	return func(c *clientConfig) {
+		c.maxResponseBody = n
+	}
+}
+
+// NewClient returns an http.Client retrying its requests with r through a
+// Transport, as a drop-in replacement for clients of
+// hashicorp/go-retryablehttp. Request bodies without a GetBody, e.g. from
+// an io.Reader, are buffered in memory up to 64 KiB so they can be
+// retried; larger ones are sent once unless WithBodyBuffer or
+// WithTempFileBuffer is passed with WithTransportOptions.
+func NewClient(r retry.ContextRetrier, opts ...ClientOption) *http.Client {
+	cfg := &clientConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	t := NewTransport(cfg.next, r, append([]TransportOption{WithBodyBuffer(defaultBufferLimit)}, cfg.transport...)...)
+	var rt http.RoundTripper = t
+	if cfg.maxResponseBody > 0 {
+		rt = limitResponses(t, cfg.maxResponseBody)
+	}
+	return &http.Client{Transport: rt, Timeout: cfg.timeout}
+}
+
+// limitResponses wraps next, limiting the response bodies to n bytes.
+func limitResponses(next http.RoundTripper, n int64) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		resp, err := next.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = &limitedBody{ReadCloser: resp.Body, left: n}
+		return resp, nil
+	})
+}
+
+// limitedBody is a response body failing with ErrResponseTooLarge past its
+// limit.
+type limitedBody struct {
+	io.ReadCloser
+	left int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.left <= 0 {
+		// Probe for data past the limit.
+		var probe [1]byte
+		n, err := b.ReadCloser.Read(probe[:])
+		if n > 0 {
+			return 0, ErrResponseTooLarge
+		}
+		return 0, err
+	}
+	if int64(len(p)) > b.left {
+		p = p[:b.left]
+	}
+	n, err := b.ReadCloser.Read(p)
+	b.left -= int64(n)
+	return n, err
+}
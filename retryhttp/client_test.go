@@ -0,0 +1,79 @@
+package retryhttp
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewClient(t *testing.T) {
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		b, _ := io.ReadAll(req.Body)
+		bodies = append(bodies, string(b))
+		if len(bodies) < 2 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte("done"))
+	}))
+	defer srv.Close()
+
+	client := NewClient(testRetrier(3))
+	// A plain io.Reader has no GetBody: the client buffers it.
+	body := io.MultiReader(strings.NewReader("small "), strings.NewReader("payload"))
+	resp, err := client.Do(newPut(t, srv.URL, body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []string{"small payload", "small payload"}, bodies)
+}
+
+func TestNewClient_LargeBody(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		calls++
+		b, _ := io.ReadAll(req.Body)
+		assert.Len(t, b, defaultBufferLimit+1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	client := NewClient(testRetrier(3))
+	body := io.MultiReader(bytes.NewReader(make([]byte, defaultBufferLimit+1)))
+	resp, err := client.Do(newPut(t, srv.URL, body))
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, 1, calls, "bodies over the limit are sent once")
+}
+
+func TestNewClient_MaxResponseBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte(req.URL.Query().Get("body")))
+	}))
+	defer srv.Close()
+
+	client := NewClient(testRetrier(1), WithMaxResponseBody(4))
+
+	resp, err := client.Get(srv.URL + "?body=four")
+	require.NoError(t, err)
+	b, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.NoError(t, err)
+	assert.Equal(t, "four", string(b))
+
+	resp, err = client.Get(srv.URL + "?body=fives")
+	require.NoError(t, err)
+	b, err = io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.ErrorIs(t, err, ErrResponseTooLarge)
+	assert.Equal(t, "five", string(b))
+}
@@ -0,0 +1,193 @@
+package retryhttp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/er-davo/retry"
+)
+
+// ErrResourceChanged is returned by Download when the resource changed
+// between two attempts, so the bytes already written cannot be resumed.
+var ErrResourceChanged = errors.New("resource changed during download")
+
+// DownloadOption configures Download.
+type DownloadOption func(*downloader)
+
+// WithDownloadClient sets the client making the requests of Download. The
+// default is http.DefaultClient.
+func WithDownloadClient(c *http.Client) DownloadOption {
+	return func(d *downloader) {
+		d.client = c
+	}
+}
+
+// WithDownloadRetrier sets the retrier of Download. The default retries
+// transport failures, interrupted bodies and the statuses of
+// retry.HTTPStatusClassifier, with the default attempts and backoff.
+func WithDownloadRetrier(r retry.ContextRetrier) DownloadOption {
+	return func(d *downloader) {
+		d.retrier = r
+	}
+}
+
+type downloader struct {
+	client  *http.Client
+	retrier retry.ContextRetrier
+	url     string
+	w       io.Writer
+
+	written      int64
+	etag         string
+	lastModified string
+}
+
+// Download writes the resource at url to w, resuming where the previous
+// attempt stopped after a transient failure instead of starting over: the
+// next attempts request the missing bytes with a Range header.
+//
+// The ETag or Last-Modified of the first response are checked against
+// the next ones, so a resource changed meanwhile is not pieced together
+// from two versions: Download fails with ErrResourceChanged instead. A
+// server ignoring the Range request is handled by skipping the bytes
+// already written. A failure to write to w is returned at once.
+func Download(ctx context.Context, url string, w io.Writer, opts ...DownloadOption) error {
+	d := &downloader{client: http.DefaultClient, url: url, w: w}
+	for _, opt := range opts {
+		opt(d)
+	}
+	if d.retrier == nil {
+		d.retrier = retry.New(retry.WithIsRetryableFunc(downloadRetryable))
+	}
+
+	return d.retrier.DoContext(ctx, d.attempt)
+}
+
+// downloadRetryable reports whether a failed attempt of Download is worth
+// retrying.
+func downloadRetryable(err error) bool {
+	var sc retry.StatusCoder
+	if errors.As(err, &sc) {
+		return retry.HTTPStatusClassifier()(err)
+	}
+	return true
+}
+
+// attempt requests the bytes not written yet and copies them to w.
+func (d *downloader) attempt(ctx context.Context, _ int) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.url, nil)
+	if err != nil {
+		return retry.Unretryable(err)
+	}
+	if d.written > 0 {
+		req.Header.Set("Range", "bytes="+strconv.FormatInt(d.written, 10)+"-")
+		if v := d.validator(); v != "" {
+			req.Header.Set("If-Range", v)
+		}
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body := io.Reader(resp.Body)
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		if d.written == 0 {
+			d.etag = resp.Header.Get("ETag")
+			d.lastModified = resp.Header.Get("Last-Modified")
+			break
+		}
+		// The range was ignored, or the resource changed.
+		if !d.unchanged(resp) {
+			return retry.Unretryable(ErrResourceChanged)
+		}
+		if _, err := io.CopyN(io.Discard, body, d.written); err != nil {
+			return err
+		}
+	case resp.StatusCode == http.StatusPartialContent && d.written > 0:
+		if !d.unchanged(resp) {
+			return retry.Unretryable(ErrResourceChanged)
+		}
+		if start, ok := rangeStart(resp); !ok || start != d.written {
+			return retry.Unretryable(fmt.Errorf("unexpected content range %q", resp.Header.Get("Content-Range")))
+		}
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && d.written > 0:
+		// The previous attempt wrote the whole resource.
+		return nil
+	default:
+		return retry.NewHTTPError(resp)
+	}
+
+	n, err := io.Copy(d.w, readerFunc(func(p []byte) (int, error) {
+		n, err := body.Read(p)
+		if err != nil && err != io.EOF {
+			err = &bodyError{err}
+		}
+		return n, err
+	}))
+	d.written += n
+	if err != nil {
+		var be *bodyError
+		if !errors.As(err, &be) {
+			return retry.Unretryable(err)
+		}
+		return be.err
+	}
+	return nil
+}
+
+// validator returns the If-Range validator of the resource: its ETag if it
+// is strong, otherwise its Last-Modified.
+func (d *downloader) validator() string {
+	if d.etag != "" && !strings.HasPrefix(d.etag, "W/") {
+		return d.etag
+	}
+	return d.lastModified
+}
+
+// unchanged reports whether resp is for the version of the resource of
+// the first response.
+func (d *downloader) unchanged(resp *http.Response) bool {
+	if d.etag != "" {
+		return resp.Header.Get("ETag") == d.etag
+	}
+	if d.lastModified != "" {
+		return resp.Header.Get("Last-Modified") == d.lastModified
+	}
+	return true
+}
+
+// rangeStart returns the first byte position of the Content-Range of resp.
+func rangeStart(resp *http.Response) (int64, bool) {
+	v, ok := strings.CutPrefix(resp.Header.Get("Content-Range"), "bytes ")
+	if !ok {
+		return 0, false
+	}
+	first, _, ok := strings.Cut(v, "-")
+	if !ok {
+		return 0, false
+	}
+	start, err := strconv.ParseInt(first, 10, 64)
+	return start, err == nil
+}
+
+// bodyError is a failure to read a response body, retried unlike a
+// failure to write to the destination.
+type bodyError struct {
+	err error
+}
+
+func (e *bodyError) Error() string { return e.err.Error() }
+
+// readerFunc adapts a function to io.Reader.
+type readerFunc func([]byte) (int, error)
+
+func (f readerFunc) Read(p []byte) (int, error) { return f(p) }
@@ -0,0 +1,116 @@
+package retryhttp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/er-davo/retry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const downloadContent = "0123456789abcdefghij"
+
+// flakyServer serves downloadContent with an ETag, aborting the first response
+// halfway through. Later responses are served by serve.
+func flakyServer(t *testing.T, serve func(w http.ResponseWriter, req *http.Request)) (*httptest.Server, *[]string) {
+	var ranges []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ranges = append(ranges, req.Header.Get("Range"))
+		if len(ranges) == 1 {
+			w.Header().Set("ETag", `"v1"`)
+			w.Header().Set("Content-Length", strconv.Itoa(len(downloadContent)))
+			_, _ = w.Write([]byte(downloadContent[:8]))
+			w.(http.Flusher).Flush()
+			panic(http.ErrAbortHandler)
+		}
+		serve(w, req)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &ranges
+}
+
+func downloadRetrier() DownloadOption {
+	return WithDownloadRetrier(retry.New(
+		retry.WithMaxAttempts(3),
+		retry.WithBackoff(retry.FixedBackoff{Interval: time.Millisecond}),
+		retry.WithIsRetryableFunc(downloadRetryable),
+	))
+}
+
+func TestDownload(t *testing.T) {
+	srv, ranges := flakyServer(t, func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		http.ServeContent(w, req, "", time.Time{}, strings.NewReader(downloadContent))
+	})
+
+	var buf bytes.Buffer
+	err := Download(context.Background(), srv.URL, &buf, downloadRetrier())
+	require.NoError(t, err)
+	assert.Equal(t, downloadContent, buf.String())
+	assert.Equal(t, []string{"", "bytes=8-"}, *ranges)
+}
+
+func TestDownload_RangeIgnored(t *testing.T) {
+	srv, _ := flakyServer(t, func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(downloadContent))
+	})
+
+	var buf bytes.Buffer
+	err := Download(context.Background(), srv.URL, &buf, downloadRetrier())
+	require.NoError(t, err)
+	assert.Equal(t, downloadContent, buf.String())
+}
+
+func TestDownload_ResourceChanged(t *testing.T) {
+	srv, ranges := flakyServer(t, func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("ETag", `"v2"`)
+		http.ServeContent(w, req, "", time.Time{}, strings.NewReader(strings.ToUpper(downloadContent)))
+	})
+
+	var buf bytes.Buffer
+	err := Download(context.Background(), srv.URL, &buf, downloadRetrier())
+	assert.ErrorIs(t, err, ErrResourceChanged)
+	assert.Len(t, *ranges, 2, "a changed resource is not retried")
+}
+
+func TestDownload_Status(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		calls++
+		http.NotFound(w, req)
+	}))
+	defer srv.Close()
+
+	err := Download(context.Background(), srv.URL, &bytes.Buffer{}, downloadRetrier())
+	var httpErr *retry.HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, http.StatusNotFound, httpErr.Code)
+	assert.Equal(t, 1, calls)
+}
+
+type failingWriter struct{ err error }
+
+func (w failingWriter) Write([]byte) (int, error) { return 0, w.err }
+
+func TestDownload_WriteError(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		calls++
+		_, _ = w.Write([]byte(downloadContent))
+	}))
+	defer srv.Close()
+
+	errDisk := errors.New("disk full")
+	err := Download(context.Background(), srv.URL, failingWriter{errDisk}, downloadRetrier())
+	assert.ErrorIs(t, err, errDisk)
+	assert.Equal(t, 1, calls, "write failures are not retried")
+}
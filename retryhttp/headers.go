@@ -0,0 +1,108 @@
+// Package retryhttp integrates the retry package with net/http clients.
+package retryhttp
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/er-davo/retry"
+)
+
+// Default header names set by Headers.
+const (
+	AttemptHeader        = "X-Retry-Attempt"
+	IdempotencyKeyHeader = "Idempotency-Key"
+)
+
+// HeaderOption configures Headers.
+type HeaderOption func(*headerConfig)
+
+type headerConfig struct {
+	attempt     string
+	idempotency string
+}
+
+// WithAttemptHeader sets the name of the header carrying the attempt
+// number. An empty name disables it.
+func WithAttemptHeader(name string) HeaderOption {
+	return func(c *headerConfig) {
+		c.attempt = name
+	}
+}
+
+// WithIdempotencyKeyHeader sets the name of the header carrying the
+// idempotency key. An empty name disables it.
+func WithIdempotencyKeyHeader(name string) HeaderOption {
+	return func(c *headerConfig) {
+		c.idempotency = name
+	}
+}
+
+type idempotencyKey struct{}
+
+// ContextWithIdempotencyKey returns a copy of ctx carrying key, which
+// Headers sends with every attempt of requests made with the context, so
+// servers can deduplicate retried requests.
+func ContextWithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKey{}, key)
+}
+
+// Headers wraps next, stamping requests made from retry attempts so
+// servers can observe and deduplicate client retries:
+//   - retried requests (attempt 1 and later) carry the attempt number in
+//     AttemptHeader
+//   - requests whose context carries a key set with
+//     ContextWithIdempotencyKey carry it in IdempotencyKeyHeader
+//
+// The attempt is read from the request context, so requests must be
+// created with the context passed to the attempt by retry's DoContext.
+// Headers already set on the request are left untouched. A nil next uses
+// http.DefaultTransport.
+func Headers(next http.RoundTripper, opts ...HeaderOption) http.RoundTripper {
+	cfg := &headerConfig{
+		attempt:     AttemptHeader,
+		idempotency: IdempotencyKeyHeader,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return next.RoundTrip(cfg.stamp(req))
+	})
+}
+
+// stamp returns req, or a clone of it carrying the configured headers.
+func (c *headerConfig) stamp(req *http.Request) *http.Request {
+	set := make(map[string]string, 2)
+	if a, ok := retry.AttemptFromContext(req.Context()); ok && a.Number > 0 && c.attempt != "" {
+		set[c.attempt] = strconv.Itoa(a.Number)
+	}
+	if key, ok := req.Context().Value(idempotencyKey{}).(string); ok && key != "" && c.idempotency != "" {
+		set[c.idempotency] = key
+	}
+
+	var clone *http.Request
+	for name, value := range set {
+		if req.Header.Get(name) != "" {
+			continue
+		}
+		if clone == nil {
+			clone = req.Clone(req.Context())
+		}
+		clone.Header.Set(name, value)
+	}
+	if clone == nil {
+		return req
+	}
+	return clone
+}
+
+// roundTripperFunc adapts a function to http.RoundTripper.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
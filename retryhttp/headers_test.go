@@ -0,0 +1,65 @@
+package retryhttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/er-davo/retry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeaders(t *testing.T) {
+	type seen struct{ attempt, key string }
+	var got []seen
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		got = append(got, seen{req.Header.Get(AttemptHeader), req.Header.Get(IdempotencyKeyHeader)})
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: Headers(nil)}
+	r := retry.New(
+		retry.WithMaxAttempts(3),
+		retry.WithBackoff(retry.FixedBackoff{Interval: time.Millisecond}),
+	)
+
+	ctx := ContextWithIdempotencyKey(context.Background(), "order-42")
+	_ = r.DoContext(ctx, func(ctx context.Context, attempt int) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, srv.URL, nil)
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Empty(t, req.Header, "the caller's request is not modified")
+		return errors.New(resp.Status)
+	})
+
+	assert.Equal(t, []seen{{"", "order-42"}, {"1", "order-42"}, {"2", "order-42"}}, got)
+}
+
+func TestHeaders_Options(t *testing.T) {
+	var got http.Header
+	next := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		got = req.Header
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	})
+	rt := Headers(next, WithAttemptHeader("X-Attempt"), WithIdempotencyKeyHeader(""))
+
+	r := retry.New(retry.WithMaxAttempts(2), retry.WithBackoff(retry.FixedBackoff{}))
+	ctx := ContextWithIdempotencyKey(context.Background(), "k")
+	_ = r.DoContext(ctx, func(ctx context.Context, attempt int) error {
+		req := httptest.NewRequestWithContext(ctx, http.MethodGet, "http://example.com", nil)
+		req.Header.Set("X-Attempt", "custom")
+		_, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+		return errors.New("retry")
+	})
+
+	assert.Equal(t, "custom", got.Get("X-Attempt"), "existing headers are kept")
+	assert.Empty(t, got.Get(IdempotencyKeyHeader))
+}
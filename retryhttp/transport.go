@@ -0,0 +1,240 @@
+package retryhttp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+
+	"github.com/er-davo/retry"
+)
+
+// defaultDrainLimit is the number of bytes of a discarded response body
+// read so its connection can be reused.
+const defaultDrainLimit = 4 << 10
+
+// TransportOption configures a Transport.
+type TransportOption func(*Transport)
+
+// WithRetryStatusCodes sets the response status codes retried by the
+// transport. The default is the set of retry.HTTPStatusClassifier.
+func WithRetryStatusCodes(codes ...int) TransportOption {
+	return func(t *Transport) {
+		t.retryStatus = retry.HTTPStatusClassifier(codes...)
+	}
+}
+
+// WithDrainLimit sets how many bytes of a discarded response body are read
+// before closing it, so the connection can be reused for the next attempt.
+// Larger bodies are closed without being read to the end.
+func WithDrainLimit(n int64) TransportOption {
+	return func(t *Transport) {
+		t.drainLimit = n
+	}
+}
+
+// WithRetryMethods adds methods to the request methods retried by the
+// transport, e.g. http.MethodPost for an API whose POST requests are known
+// to be idempotent.
+func WithRetryMethods(methods ...string) TransportOption {
+	return func(t *Transport) {
+		t.methods = append(t.methods, methods...)
+	}
+}
+
+// defaultRetryMethods returns the idempotent request methods retried by
+// default.
+func defaultRetryMethods() []string {
+	return []string{
+		http.MethodGet,
+		http.MethodHead,
+		http.MethodPut,
+		http.MethodDelete,
+		http.MethodOptions,
+	}
+}
+
+// Transport is an http.RoundTripper retrying requests with a retrier.
+//
+// A request is retried when the underlying RoundTripper fails, or when the
+// response status is retryable, see WithRetryStatusCodes. Responses with
+// a retryable status fail the attempt with a *retry.HTTPError, so the
+// retrier classifies them and honors their Retry-After; the response of
+// the last attempt is returned when the retrier gives up on it. Discarded
+// responses are drained and closed between attempts. An attempt timeout of
+// the retrier bounds the wait for a response, not the reading of the body
+// of the response returned, which only the request context cancels.
+//
+// Only idempotent requests are retried: those with a GET, HEAD, PUT,
+// DELETE or OPTIONS method, see WithRetryMethods, and those carrying an
+// IdempotencyKeyHeader, or a key set with ContextWithIdempotencyKey.
+// Other requests are sent once.
+//
+// Requests with a body are rewound through their GetBody for every
+// attempt; requests with a body but no GetBody are sent only once, unless
+// they are buffered, see WithBodyBuffer and WithTempFileBuffer.
+type Transport struct {
+	next        http.RoundTripper
+	retrier     retry.ContextRetrier
+	retryStatus retry.IsRetryableFunc
+	methods     []string
+	drainLimit  int64
+	bufferLimit int64
+	tempFile    bool
+	tempDir     string
+	tempLimit   int64
+}
+
+// NewTransport returns a Transport sending requests through next, retried
+// with r. A nil next uses http.DefaultTransport and a nil r retry.New().
+//
+// The context of every attempt is set on its request, so next may be
+// wrapped with Headers.
+func NewTransport(next http.RoundTripper, r retry.ContextRetrier, opts ...TransportOption) *Transport {
+	t := &Transport{
+		next:        next,
+		retrier:     r,
+		retryStatus: retry.HTTPStatusClassifier(),
+		methods:     defaultRetryMethods(),
+		drainLimit:  defaultDrainLimit,
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	if t.next == nil {
+		t.next = http.DefaultTransport
+	}
+	if t.retrier == nil {
+		t.retrier = retry.New()
+	}
+	return t
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !t.idempotent(req) {
+		return t.next.RoundTrip(req)
+	}
+	if !rewindable(req) && (t.bufferLimit > 0 || t.tempFile) {
+		breq, release, err := t.buffer(req)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+		req = breq
+	}
+	if !rewindable(req) {
+		return t.next.RoundTrip(req)
+	}
+
+	var (
+		last    *http.Response
+		lastErr error
+	)
+	err := t.retrier.DoContext(req.Context(), func(ctx context.Context, attempt int) error {
+		if last != nil {
+			t.drain(last)
+			last, lastErr = nil, nil
+		}
+
+		areq, err := rewind(ctx, req, attempt)
+		if err != nil {
+			return err
+		}
+		resp, err := t.send(req.Context(), areq)
+		if err != nil {
+			return err
+		}
+
+		last = resp
+		if httpErr := retry.NewHTTPError(resp); t.retryStatus(httpErr) {
+			lastErr = httpErr
+			return httpErr
+		}
+		return nil
+	})
+
+	if last != nil && (err == nil || errors.Is(err, lastErr)) {
+		return last, nil
+	}
+	if last != nil {
+		t.drain(last)
+	}
+	return nil, err
+}
+
+// idempotent reports whether req may be retried.
+func (t *Transport) idempotent(req *http.Request) bool {
+	method := req.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	if slices.Contains(t.methods, method) || req.Header.Get(IdempotencyKeyHeader) != "" {
+		return true
+	}
+	key, _ := req.Context().Value(idempotencyKey{}).(string)
+	return key != ""
+}
+
+// send sends areq, whose context is the one of its attempt, so an attempt
+// timeout bounds the wait for the response. The response body outlives the
+// attempt: reading it is canceled with ctx, the context of the call, and
+// its resources are released when it is closed.
+func (t *Transport) send(ctx context.Context, areq *http.Request) (*http.Response, error) {
+	actx := areq.Context()
+	rctx, cancel := context.WithCancel(context.WithoutCancel(actx))
+	stopAttempt := context.AfterFunc(actx, cancel)
+
+	resp, err := t.next.RoundTrip(areq.WithContext(rctx))
+	if err != nil {
+		cancel()
+		if actx.Err() != nil {
+			// Report the end of the attempt, e.g. its timeout, rather than
+			// the cancellation of the request.
+			return nil, context.Cause(actx)
+		}
+		return nil, err
+	}
+	if !stopAttempt() {
+		// The attempt ended as the response arrived.
+		_ = resp.Body.Close()
+		return nil, context.Cause(actx)
+	}
+
+	stopCall := context.AfterFunc(ctx, cancel)
+	body := resp.Body
+	resp.Body = readCloser{body, func() error {
+		stopCall()
+		cancel()
+		return body.Close()
+	}}
+	return resp, nil
+}
+
+// drain reads up to the drain limit of resp's body and closes it.
+func (t *Transport) drain(resp *http.Response) {
+	_, _ = io.CopyN(io.Discard, resp.Body, t.drainLimit)
+	_ = resp.Body.Close()
+}
+
+// rewindable reports whether req can be sent more than once.
+func rewindable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// rewind returns the request of an attempt: req with the attempt context
+// and, after the first attempt, a fresh body.
+func rewind(ctx context.Context, req *http.Request, attempt int) (*http.Request, error) {
+	areq := req.WithContext(ctx)
+	if attempt == 0 || req.Body == nil || req.Body == http.NoBody {
+		return areq, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	areq.Body = body
+	return areq, nil
+}
@@ -0,0 +1,211 @@
+package retryhttp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/er-davo/retry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testRetrier(attempts int) retry.ContextRetrier {
+	return retry.New(
+		retry.WithMaxAttempts(attempts),
+		retry.WithBackoff(retry.FixedBackoff{Interval: time.Millisecond}),
+	)
+}
+
+func newPut(t *testing.T, url string, body io.Reader) *http.Request {
+	req, err := http.NewRequest(http.MethodPut, url, body)
+	require.NoError(t, err)
+	return req
+}
+
+func TestTransport(t *testing.T) {
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		b, _ := io.ReadAll(req.Body)
+		bodies = append(bodies, string(b))
+		if len(bodies) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte("busy"))
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: NewTransport(nil, testRetrier(3))}
+	resp, err := client.Do(newPut(t, srv.URL, strings.NewReader("payload")))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	b, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "ok", string(b))
+	assert.Equal(t, []string{"payload", "payload", "payload"}, bodies, "the body is rewound for every attempt")
+}
+
+func TestTransport_GivesUp(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		calls++
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte("slow down"))
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: NewTransport(nil, testRetrier(2))}
+	resp, err := client.Get(srv.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	b, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode, "the last response is returned")
+	assert.Equal(t, "slow down", string(b))
+}
+
+func TestTransport_StatusCodes(t *testing.T) {
+	calls := 0
+	next := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return &http.Response{StatusCode: http.StatusServiceUnavailable, Body: http.NoBody}, nil
+	})
+	rt := NewTransport(next, testRetrier(3), WithRetryStatusCodes(http.StatusConflict))
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com", nil)
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, 1, calls, "other statuses are not retried")
+}
+
+func TestTransport_Errors(t *testing.T) {
+	errDial := errors.New("dial failed")
+	calls := 0
+	next := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return nil, errDial
+	})
+	rt := NewTransport(next, testRetrier(3))
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com", nil)
+	resp, err := rt.RoundTrip(req)
+	assert.Nil(t, resp)
+	assert.ErrorIs(t, err, errDial)
+	assert.Equal(t, 3, calls)
+}
+
+func TestTransport_NotRewindable(t *testing.T) {
+	calls := 0
+	next := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return nil, errors.New("fail")
+	})
+	rt := NewTransport(next, testRetrier(3))
+
+	req := httptest.NewRequest(http.MethodPut, "http://example.com", io.NopCloser(strings.NewReader("x")))
+	req.GetBody = nil
+	_, err := rt.RoundTrip(req)
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls, "a body without GetBody is sent once")
+}
+
+func TestTransport_Idempotency(t *testing.T) {
+	calls := 0
+	next := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return &http.Response{StatusCode: http.StatusServiceUnavailable, Body: http.NoBody}, nil
+	})
+
+	tests := []struct {
+		name  string
+		req   func() *http.Request
+		opts  []TransportOption
+		calls int
+	}{
+		{
+			name:  "idempotent method",
+			req:   func() *http.Request { return httptest.NewRequest(http.MethodDelete, "http://example.com", nil) },
+			calls: 3,
+		},
+		{
+			name:  "non-idempotent method",
+			req:   func() *http.Request { return httptest.NewRequest(http.MethodPost, "http://example.com", nil) },
+			calls: 1,
+		},
+		{
+			name: "idempotency key header",
+			req: func() *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "http://example.com", nil)
+				req.Header.Set(IdempotencyKeyHeader, "k")
+				return req
+			},
+			calls: 3,
+		},
+		{
+			name: "idempotency key context",
+			req: func() *http.Request {
+				ctx := ContextWithIdempotencyKey(context.Background(), "k")
+				return httptest.NewRequestWithContext(ctx, http.MethodPatch, "http://example.com", nil)
+			},
+			calls: 3,
+		},
+		{
+			name:  "opt-in method",
+			req:   func() *http.Request { return httptest.NewRequest(http.MethodPost, "http://example.com", nil) },
+			opts:  []TransportOption{WithRetryMethods(http.MethodPost)},
+			calls: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls = 0
+			rt := NewTransport(next, testRetrier(3), tt.opts...)
+			resp, err := rt.RoundTrip(tt.req())
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+			assert.Equal(t, tt.calls, calls)
+		})
+	}
+}
+
+func TestTransport_AttemptTimeout(t *testing.T) {
+	body := strings.Repeat("x", 4<<20)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Query().Has("hang") {
+			<-req.Context().Done()
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	r := retry.New(
+		retry.WithMaxAttempts(2),
+		retry.WithBackoff(retry.FixedBackoff{}),
+		retry.WithAttemptTimeout(100*time.Millisecond),
+	)
+	client := &http.Client{Transport: NewTransport(nil, r)}
+
+	resp, err := client.Get(srv.URL)
+	require.NoError(t, err)
+	time.Sleep(150 * time.Millisecond) // past the attempt timeout
+	b, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.NoError(t, err, "the body outlives the attempt")
+	assert.Len(t, b, len(body))
+
+	_, err = client.Get(srv.URL + "?hang")
+	assert.ErrorIs(t, err, retry.ErrAttemptTimeout, "the attempt timeout bounds the wait for the response")
+}
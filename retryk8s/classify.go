@@ -0,0 +1,142 @@
+// Package retryk8s integrates the retry package with Kubernetes API clients.
+//
+// Errors are recognized through the APIStatus shape of
+// k8s.io/apimachinery/pkg/api/errors (a Status method returning a
+// metav1.Status), so apimachinery does not need to be imported.
+package retryk8s
+
+import (
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/er-davo/retry"
+)
+
+// Status reasons as defined by metav1.StatusReason.
+const (
+	reasonConflict        = "Conflict"
+	reasonServerTimeout   = "ServerTimeout"
+	reasonTooManyRequests = "TooManyRequests"
+)
+
+// knownReasons are the status reasons apimachinery knows, except the
+// unknown, empty reason. Errors with other reasons are classified by their
+// code, as apierrors does.
+var knownReasons = map[string]bool{
+	"Unauthorized":          true,
+	"Forbidden":             true,
+	"NotFound":              true,
+	"AlreadyExists":         true,
+	reasonConflict:          true,
+	"Gone":                  true,
+	"Invalid":               true,
+	reasonServerTimeout:     true,
+	"StoreReadError":        true,
+	"Timeout":               true,
+	reasonTooManyRequests:   true,
+	"BadRequest":            true,
+	"MethodNotAllowed":      true,
+	"NotAcceptable":         true,
+	"RequestEntityTooLarge": true,
+	"UnsupportedMediaType":  true,
+	"InternalError":         true,
+	"Expired":               true,
+	"ServiceUnavailable":    true,
+}
+
+// IsConflict reports whether err is a Kubernetes API conflict, matching
+// apierrors.IsConflict: its reason is Conflict, or a reason apimachinery
+// does not know with the code 409.
+func IsConflict(err error) bool {
+	reason, code, ok := reasonAndCode(err)
+	if !ok {
+		return false
+	}
+	return reason == reasonConflict || (!knownReasons[reason] && code == http.StatusConflict)
+}
+
+// IsServerTimeout reports whether err is a Kubernetes API server timeout,
+// matching apierrors.IsServerTimeout.
+func IsServerTimeout(err error) bool {
+	reason, _, ok := reasonAndCode(err)
+	return ok && reason == reasonServerTimeout
+}
+
+// IsTooManyRequests reports whether err reports that the API server is
+// throttling the client, matching apierrors.IsTooManyRequests.
+func IsTooManyRequests(err error) bool {
+	reason, code, ok := reasonAndCode(err)
+	if !ok {
+		return false
+	}
+	return reason == reasonTooManyRequests || code == http.StatusTooManyRequests
+}
+
+// IsRetryable reports whether err is a conflict, a server timeout or
+// a throttling response.
+func IsRetryable(err error) bool {
+	return IsConflict(err) || IsServerTimeout(err) || IsTooManyRequests(err)
+}
+
+// RetryOnConflict returns a Retrier with the semantics of client-go's
+// retry.RetryOnConflict(retry.DefaultRetry, ...): up to 5 attempts,
+// 10ms apart with 10% jitter, retrying only conflicts.
+// Additional opts are applied on top of these defaults.
+func RetryOnConflict(opts ...retry.RetryOption) retry.ContextRetrier {
+	defaults := []retry.RetryOption{
+		retry.WithMaxAttempts(5),
+		retry.WithBackoff(retry.FixedBackoff{Interval: 10 * time.Millisecond, Jitter: 0.1}),
+		retry.WithIsRetryableFunc(IsConflict),
+	}
+	return retry.New(append(defaults, opts...)...)
+}
+
+// reasonAndCode extracts the status reason and code from the first error in
+// err's tree with a Status method returning a struct with Reason and Code
+// fields. The tree is walked in the order of errors.As, including the
+// errors wrapping several errors, such as the ones of errors.Join.
+func reasonAndCode(err error) (reason string, code int32, ok bool) {
+	if err == nil {
+		return "", 0, false
+	}
+	if reason, code, ok := statusOf(err); ok {
+		return reason, code, true
+	}
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		return reasonAndCode(u.Unwrap())
+	case interface{ Unwrap() []error }:
+		for _, e := range u.Unwrap() {
+			if reason, code, ok := reasonAndCode(e); ok {
+				return reason, code, true
+			}
+		}
+	}
+	return "", 0, false
+}
+
+// statusOf extracts the status reason and code of err itself.
+func statusOf(err error) (reason string, code int32, ok bool) {
+	m := reflect.ValueOf(err).MethodByName("Status")
+	if !m.IsValid() || m.Type().NumIn() != 0 || m.Type().NumOut() != 1 {
+		return "", 0, false
+	}
+
+	st := m.Call(nil)[0]
+	if st.Kind() == reflect.Pointer {
+		if st.IsNil() {
+			return "", 0, false
+		}
+		st = st.Elem()
+	}
+	if st.Kind() != reflect.Struct {
+		return "", 0, false
+	}
+
+	r, c := st.FieldByName("Reason"), st.FieldByName("Code")
+	if !r.IsValid() || r.Kind() != reflect.String || !c.IsValid() || !c.CanInt() {
+		return "", 0, false
+	}
+	return r.String(), int32(c.Int()), true
+}
@@ -0,0 +1,92 @@
+package retryk8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type statusReason string
+
+// status mirrors the relevant fields of metav1.Status.
+type status struct {
+	Message string
+	Reason  statusReason
+	Code    int32
+}
+
+// statusError mirrors apierrors.StatusError.
+type statusError struct {
+	ErrStatus status
+}
+
+func (e *statusError) Error() string  { return e.ErrStatus.Message }
+func (e *statusError) Status() status { return e.ErrStatus }
+
+func newStatusError(reason string, code int32) error {
+	return &statusError{ErrStatus: status{Message: reason, Reason: statusReason(reason), Code: code}}
+}
+
+func TestClassifiers(t *testing.T) {
+	tests := []struct {
+		name          string
+		err           error
+		conflict      bool
+		serverTimeout bool
+		tooMany       bool
+	}{
+		{"conflict", newStatusError("Conflict", 409), true, false, false},
+		{"unknown reason 409", newStatusError("", 409), true, false, false},
+		{"unrecognized reason 409", newStatusError("Superseded", 409), true, false, false},
+		{"known reason 409", newStatusError("AlreadyExists", 409), false, false, false},
+		{"joined conflict", errors.Join(errors.New("rollback"), newStatusError("Conflict", 409)), true, false, false},
+		{"multi-wrapped conflict", fmt.Errorf("%w: %w", errors.New("update"), newStatusError("Conflict", 409)), true, false, false},
+		{"server timeout", fmt.Errorf("update: %w", newStatusError("ServerTimeout", 500)), false, true, false},
+		{"too many requests", newStatusError("TooManyRequests", 429), false, false, true},
+		{"code 429", newStatusError("", 429), false, false, true},
+		{"not found", newStatusError("NotFound", 404), false, false, false},
+		{"plain", errors.New("boom"), false, false, false},
+		{"nil", nil, false, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.conflict, IsConflict(tt.err))
+			assert.Equal(t, tt.serverTimeout, IsServerTimeout(tt.err))
+			assert.Equal(t, tt.tooMany, IsTooManyRequests(tt.err))
+			assert.Equal(t, tt.conflict || tt.serverTimeout || tt.tooMany, IsRetryable(tt.err))
+		})
+	}
+}
+
+func TestRetryOnConflict(t *testing.T) {
+	calls := 0
+	err := RetryOnConflict().Do(context.Background(), func(attempt int) error {
+		calls++
+		if attempt < 2 {
+			return newStatusError("Conflict", 409)
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+
+	calls = 0
+	err = RetryOnConflict().Do(context.Background(), func(attempt int) error {
+		calls++
+		return newStatusError("NotFound", 404)
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+
+	calls = 0
+	err = RetryOnConflict().Do(context.Background(), func(attempt int) error {
+		calls++
+		return newStatusError("Conflict", 409)
+	})
+	assert.True(t, IsConflict(err))
+	assert.Equal(t, 5, calls)
+}
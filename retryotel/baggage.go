@@ -0,0 +1,28 @@
+package retryotel
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/er-davo/retry"
+	"go.opentelemetry.io/otel/baggage"
+)
+
+// AttemptBaggage returns a retry.AttemptMiddleware stamping a
+// "retry.attempt" baggage member with the attempt number on the context of
+// every attempt, so downstream services receiving propagated baggage can
+// tell they are handling a retried request. Baggage already on the caller's
+// context is preserved, as attempt contexts derive from it.
+func AttemptBaggage() retry.AttemptMiddleware {
+	return func(next retry.ContextAttemptFunc) retry.ContextAttemptFunc {
+		return func(ctx context.Context, attempt int) error {
+			m, err := baggage.NewMemberRaw(string(AttemptKey), strconv.Itoa(attempt))
+			if err == nil {
+				if b, err := baggage.FromContext(ctx).SetMember(m); err == nil {
+					ctx = baggage.ContextWithBaggage(ctx, b)
+				}
+			}
+			return next(ctx, attempt)
+		}
+	}
+}
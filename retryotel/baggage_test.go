@@ -0,0 +1,39 @@
+package retryotel
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/er-davo/retry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/baggage"
+)
+
+func TestAttemptBaggage(t *testing.T) {
+	tenant, err := baggage.NewMember("tenant", "acme")
+	require.NoError(t, err)
+	b, err := baggage.New(tenant)
+	require.NoError(t, err)
+	ctx := baggage.ContextWithBaggage(context.Background(), b)
+
+	r := retry.New(
+		retry.WithMaxAttempts(2),
+		retry.WithBackoff(retry.FixedBackoff{Interval: time.Millisecond}),
+		retry.WithAttemptTimeout(time.Second),
+		retry.WithAttemptMiddleware(AttemptBaggage()),
+	)
+	var seen []string
+	_ = r.DoContext(ctx, func(ctx context.Context, attempt int) error {
+		got := baggage.FromContext(ctx)
+		assert.Equal(t, "acme", got.Member("tenant").Value())
+		assert.Equal(t, strconv.Itoa(attempt), got.Member("retry.attempt").Value())
+		seen = append(seen, got.Member("retry.attempt").Value())
+		return errors.New("boom")
+	})
+	assert.Equal(t, []string{"0", "1"}, seen)
+	assert.Empty(t, baggage.FromContext(ctx).Member("retry.attempt").Value())
+}
@@ -0,0 +1,157 @@
+// Package retryotel integrates the retry package with OpenTelemetry.
+package retryotel
+
+import (
+	"context"
+	"time"
+
+	"github.com/er-davo/retry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Option configures the OpenTelemetry integration.
+type Option func(*config)
+
+type config struct {
+	attrs  []attribute.KeyValue
+	filter attribute.Filter
+}
+
+// WithAttributes sets attributes recorded with every measurement,
+// typically identifying the retrier or the dependency it calls.
+func WithAttributes(attrs ...attribute.KeyValue) Option {
+	return func(c *config) {
+		c.attrs = append(c.attrs, attrs...)
+	}
+}
+
+// WithAttributeFilter keeps only the attributes for which filter returns
+// true, so attributes shared with other instrumentation can be dropped to
+// control metric cardinality, e.g.
+// attribute.NewDenyKeysFilter("retrier").
+func WithAttributeFilter(filter attribute.Filter) Option {
+	return func(c *config) {
+		c.filter = filter
+	}
+}
+
+func newConfig(opts []Option) *config {
+	c := &config{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.filter != nil {
+		attrs := c.attrs[:0:0]
+		for _, kv := range c.attrs {
+			if c.filter(kv) {
+				attrs = append(attrs, kv)
+			}
+		}
+		c.attrs = attrs
+	}
+	return c
+}
+
+// NewMetrics creates a retry.Metrics recording OpenTelemetry instruments
+// from meter:
+//   - retry.attempts, retry.retries, retry.successes and retry.exhausted counters
+//   - retry.successes_after_retry counter
+//   - retry.backoff.duration and retry.attempt.duration histograms, in seconds
+//   - retry.attempts_to_success histogram
+//   - retry.in_flight and retry.sleeping up-down counters
+func NewMetrics(meter metric.Meter, opts ...Option) (retry.Metrics, error) {
+	cfg := newConfig(opts)
+	m := &metrics{attrs: metric.WithAttributes(cfg.attrs...)}
+
+	var err error
+	if m.attempts, err = meter.Int64Counter("retry.attempts",
+		metric.WithDescription("Number of attempts made."),
+		metric.WithUnit("{attempt}")); err != nil {
+		return nil, err
+	}
+	if m.retries, err = meter.Int64Counter("retry.retries",
+		metric.WithDescription("Number of retries scheduled after a failed attempt."),
+		metric.WithUnit("{retry}")); err != nil {
+		return nil, err
+	}
+	if m.successes, err = meter.Int64Counter("retry.successes",
+		metric.WithDescription("Number of calls that eventually succeeded."),
+		metric.WithUnit("{call}")); err != nil {
+		return nil, err
+	}
+	if m.retriedSuccess, err = meter.Int64Counter("retry.successes_after_retry",
+		metric.WithDescription("Number of calls that succeeded after at least one retry."),
+		metric.WithUnit("{call}")); err != nil {
+		return nil, err
+	}
+	if m.exhausted, err = meter.Int64Counter("retry.exhausted",
+		metric.WithDescription("Number of calls that ran out of attempts."),
+		metric.WithUnit("{call}")); err != nil {
+		return nil, err
+	}
+	if m.backoff, err = meter.Float64Histogram("retry.backoff.duration",
+		metric.WithDescription("Wait before each retry."),
+		metric.WithUnit("s")); err != nil {
+		return nil, err
+	}
+	if m.attemptDuration, err = meter.Float64Histogram("retry.attempt.duration",
+		metric.WithDescription("Duration of each attempt."),
+		metric.WithUnit("s")); err != nil {
+		return nil, err
+	}
+	if m.toSuccess, err = meter.Int64Histogram("retry.attempts_to_success",
+		metric.WithDescription("Number of attempts successful calls needed."),
+		metric.WithUnit("{attempt}"),
+		metric.WithExplicitBucketBoundaries(1, 2, 3, 4, 5, 6, 8, 10)); err != nil {
+		return nil, err
+	}
+	if m.inFlight, err = meter.Int64UpDownCounter("retry.in_flight",
+		metric.WithDescription("Number of calls in progress."),
+		metric.WithUnit("{call}")); err != nil {
+		return nil, err
+	}
+	if m.sleeping, err = meter.Int64UpDownCounter("retry.sleeping",
+		metric.WithDescription("Number of calls waiting between attempts."),
+		metric.WithUnit("{call}")); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+type metrics struct {
+	attrs metric.MeasurementOption
+
+	attempts        metric.Int64Counter
+	retries         metric.Int64Counter
+	successes       metric.Int64Counter
+	retriedSuccess  metric.Int64Counter
+	exhausted       metric.Int64Counter
+	backoff         metric.Float64Histogram
+	attemptDuration metric.Float64Histogram
+	toSuccess       metric.Int64Histogram
+	inFlight        metric.Int64UpDownCounter
+	sleeping        metric.Int64UpDownCounter
+}
+
+func (m *metrics) IncAttempt()           { m.attempts.Add(context.Background(), 1, m.attrs) }
+func (m *metrics) IncRetry()             { m.retries.Add(context.Background(), 1, m.attrs) }
+func (m *metrics) IncSuccess()           { m.successes.Add(context.Background(), 1, m.attrs) }
+func (m *metrics) IncSuccessAfterRetry() { m.retriedSuccess.Add(context.Background(), 1, m.attrs) }
+func (m *metrics) IncExhausted()         { m.exhausted.Add(context.Background(), 1, m.attrs) }
+
+func (m *metrics) ObserveDelay(d time.Duration) {
+	m.backoff.Record(context.Background(), d.Seconds(), m.attrs)
+}
+
+func (m *metrics) ObserveAttemptDuration(d time.Duration) {
+	m.attemptDuration.Record(context.Background(), d.Seconds(), m.attrs)
+}
+
+func (m *metrics) ObserveAttemptsToSuccess(n int) {
+	m.toSuccess.Record(context.Background(), int64(n), m.attrs)
+}
+
+func (m *metrics) AddInFlight(delta int) { m.inFlight.Add(context.Background(), int64(delta), m.attrs) }
+func (m *metrics) AddSleeping(delta int) { m.sleeping.Add(context.Background(), int64(delta), m.attrs) }
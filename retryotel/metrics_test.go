@@ -0,0 +1,103 @@
+package retryotel
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/er-davo/retry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func collect(t *testing.T, reader sdkmetric.Reader) map[string]metricdata.Aggregation {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+
+	got := make(map[string]metricdata.Aggregation)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			got[m.Name] = m.Data
+		}
+	}
+	return got
+}
+
+func sum(t *testing.T, data metricdata.Aggregation) int64 {
+	t.Helper()
+	s, ok := data.(metricdata.Sum[int64])
+	require.True(t, ok)
+	var total int64
+	for _, dp := range s.DataPoints {
+		total += dp.Value
+	}
+	return total
+}
+
+func TestNewMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	m, err := NewMetrics(provider.Meter("test"), WithAttributes(attribute.String("retrier", "payments")))
+	require.NoError(t, err)
+
+	r := retry.New(
+		retry.WithMaxAttempts(3),
+		retry.WithBackoff(retry.FixedBackoff{Interval: time.Millisecond}),
+		retry.WithMetrics(m),
+	)
+	_ = r.Do(context.Background(), func(attempt int) error {
+		if attempt < 2 {
+			return errors.New("boom")
+		}
+		return nil
+	})
+
+	got := collect(t, reader)
+	assert.Equal(t, int64(3), sum(t, got["retry.attempts"]))
+	assert.Equal(t, int64(2), sum(t, got["retry.retries"]))
+	assert.Equal(t, int64(1), sum(t, got["retry.successes"]))
+	assert.Equal(t, int64(1), sum(t, got["retry.successes_after_retry"]))
+	assert.NotContains(t, got, "retry.exhausted")
+	assert.Zero(t, sum(t, got["retry.in_flight"]))
+	assert.Zero(t, sum(t, got["retry.sleeping"]))
+
+	backoff, ok := got["retry.backoff.duration"].(metricdata.Histogram[float64])
+	require.True(t, ok)
+	require.Len(t, backoff.DataPoints, 1)
+	assert.Equal(t, uint64(2), backoff.DataPoints[0].Count)
+
+	toSuccess, ok := got["retry.attempts_to_success"].(metricdata.Histogram[int64])
+	require.True(t, ok)
+	require.Len(t, toSuccess.DataPoints, 1)
+	assert.Equal(t, int64(3), toSuccess.DataPoints[0].Sum)
+
+	v, ok := backoff.DataPoints[0].Attributes.Value("retrier")
+	assert.True(t, ok)
+	assert.Equal(t, "payments", v.AsString())
+}
+
+func TestWithAttributeFilter(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	m, err := NewMetrics(provider.Meter("test"),
+		WithAttributes(attribute.String("retrier", "payments"), attribute.String("host", "db-17")),
+		WithAttributeFilter(attribute.NewDenyKeysFilter("host")),
+	)
+	require.NoError(t, err)
+	m.IncAttempt()
+
+	s, ok := collect(t, reader)["retry.attempts"].(metricdata.Sum[int64])
+	require.True(t, ok)
+	require.Len(t, s.DataPoints, 1)
+	attrs := s.DataPoints[0].Attributes
+	assert.Equal(t, 1, attrs.Len())
+	_, ok = attrs.Value("host")
+	assert.False(t, ok)
+}
@@ -0,0 +1,52 @@
+package retryotel
+
+import (
+	"context"
+
+	"github.com/er-davo/retry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Span attribute keys set on attempt spans.
+const (
+	AttemptKey = attribute.Key("retry.attempt")
+	DelayKey   = attribute.Key("retry.delay_ms")
+)
+
+// AttemptSpans returns a retry.AttemptMiddleware that starts a child span
+// of the caller's span for every attempt, named "retry.attempt", recording
+// the attempt number, the delay that preceded it and its error status:
+//
+//	r := retry.New(retry.WithAttemptMiddleware(retryotel.AttemptSpans(tracer)))
+//
+// Attempts run through DoContext receive the span's context; use it for
+// outgoing calls so they are parented to the attempt. Calls sampled out by
+// retry.WithTelemetrySampling get no spans.
+func AttemptSpans(tracer trace.Tracer, opts ...Option) retry.AttemptMiddleware {
+	cfg := newConfig(opts)
+
+	return func(next retry.ContextAttemptFunc) retry.ContextAttemptFunc {
+		return func(ctx context.Context, attempt int) error {
+			if !retry.TelemetrySampled(ctx) {
+				return next(ctx, attempt)
+			}
+
+			attrs := append([]attribute.KeyValue{AttemptKey.Int(attempt)}, cfg.attrs...)
+			if a, ok := retry.AttemptFromContext(ctx); ok && a.Delay > 0 {
+				attrs = append(attrs, DelayKey.Int64(a.Delay.Milliseconds()))
+			}
+
+			ctx, span := tracer.Start(ctx, "retry.attempt", trace.WithAttributes(attrs...))
+			defer span.End()
+
+			err := next(ctx, attempt)
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			}
+			return err
+		}
+	}
+}
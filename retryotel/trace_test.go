@@ -0,0 +1,66 @@
+package retryotel
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/er-davo/retry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestAttemptSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	tracer := provider.Tracer("test")
+
+	r := retry.New(
+		retry.WithMaxAttempts(3),
+		retry.WithBackoff(retry.FixedBackoff{Interval: 2 * time.Millisecond}),
+		retry.WithAttemptMiddleware(AttemptSpans(tracer, WithAttributes(attribute.String("dep", "db")))),
+	)
+
+	ctx, parent := tracer.Start(context.Background(), "parent")
+	err := r.DoContext(ctx, func(ctx context.Context, attempt int) error {
+		assert.NotEqual(t, parent.SpanContext().SpanID(), trace.SpanContextFromContext(ctx).SpanID())
+		if attempt == 0 {
+			return errors.New("boom")
+		}
+		return nil
+	})
+	parent.End()
+	require.NoError(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+
+	first, second := spans[0], spans[1]
+	assert.Equal(t, "retry.attempt", first.Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), first.Parent().SpanID())
+	assert.Equal(t, codes.Error, first.Status().Code)
+	assert.Contains(t, first.Attributes(), AttemptKey.Int(0))
+	assert.Contains(t, first.Attributes(), attribute.String("dep", "db"))
+
+	assert.Equal(t, "retry.attempt", second.Name())
+	assert.Equal(t, codes.Unset, second.Status().Code)
+	assert.Contains(t, second.Attributes(), DelayKey.Int64(2))
+}
+
+func TestAttemptSpans_SampledOut(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	r := retry.New(
+		retry.WithAttemptMiddleware(AttemptSpans(provider.Tracer("test"))),
+		retry.WithTelemetrySampling(0),
+	)
+	require.NoError(t, r.DoContext(context.Background(), func(ctx context.Context, attempt int) error { return nil }))
+	assert.Empty(t, recorder.Ended())
+}
@@ -0,0 +1,239 @@
+// Package retryprom provides Prometheus metrics for the retry package.
+//
+// A single Collector is registered once and hands out retry.Metrics
+// implementations labeled by retrier name:
+//
+//	c := retryprom.NewCollector()
+//	prometheus.MustRegister(c)
+//
+//	r := retry.New(retry.WithMetrics(c.Metrics("payments")))
+//
+// Collector is also a retry.MetricsProvider, labeling metrics with the name
+// set by retry.WithName:
+//
+//	r := retry.New(retry.WithName("payments"), retry.WithMetricsProvider(c))
+package retryprom
+
+import (
+	"hash/fnv"
+	"strconv"
+	"time"
+
+	"github.com/er-davo/retry"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Option configures a Collector.
+type Option func(*config)
+
+type config struct {
+	namespace   string
+	buckets     []float64
+	noLabel     bool
+	nameBuckets uint32
+}
+
+// WithNamespace sets the namespace prefixed to every metric name.
+func WithNamespace(namespace string) Option {
+	return func(c *config) {
+		c.namespace = namespace
+	}
+}
+
+// WithBuckets sets the histogram buckets, in seconds, used for backoff
+// and attempt durations. By default prometheus.DefBuckets is used.
+func WithBuckets(buckets []float64) Option {
+	return func(c *config) {
+		c.buckets = buckets
+	}
+}
+
+// WithoutRetrierLabel drops the "retrier" label, aggregating the metrics of
+// all retriers sharing the collector.
+func WithoutRetrierLabel() Option {
+	return func(c *config) {
+		c.noLabel = true
+	}
+}
+
+// WithHashedNames bounds the cardinality of the "retrier" label by
+// replacing names with one of n hash buckets, "h0" to "h<n-1>", for
+// services deriving retrier names from high-cardinality values such as
+// hosts or tenants. A value of 0 keeps names as is.
+func WithHashedNames(n int) Option {
+	return func(c *config) {
+		c.nameBuckets = uint32(max(n, 0))
+	}
+}
+
+// labelValues returns the label values for the retrier name.
+func (c *config) labelValues(name string) []string {
+	switch {
+	case c.noLabel:
+		return nil
+	case c.nameBuckets > 0:
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(name))
+		return []string{"h" + strconv.FormatUint(uint64(h.Sum32()%c.nameBuckets), 10)}
+	}
+	return []string{name}
+}
+
+// Collector is a prometheus.Collector exposing retry metrics:
+//   - retry_attempts_total
+//   - retry_retries_total
+//   - retry_successes_total
+//   - retry_successes_after_retry_total
+//   - retry_exhausted_total
+//   - retry_backoff_seconds
+//   - retry_attempt_duration_seconds
+//   - retry_attempts_to_success
+//   - retry_in_flight and retry_sleeping gauges
+//
+// All metrics carry a "retrier" label, unless WithoutRetrierLabel is set.
+type Collector struct {
+	cfg             *config
+	attempts        *prometheus.CounterVec
+	retries         *prometheus.CounterVec
+	successes       *prometheus.CounterVec
+	retriedSuccess  *prometheus.CounterVec
+	exhausted       *prometheus.CounterVec
+	backoff         *prometheus.HistogramVec
+	attemptDuration *prometheus.HistogramVec
+	toSuccess       *prometheus.HistogramVec
+	inFlight        *prometheus.GaugeVec
+	sleeping        *prometheus.GaugeVec
+}
+
+// NewCollector creates a new Collector.
+func NewCollector(opts ...Option) *Collector {
+	cfg := &config{
+		buckets: prometheus.DefBuckets,
+	}
+
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	var labels []string
+	if !cfg.noLabel {
+		labels = []string{"retrier"}
+	}
+	counter := func(name, help string) *prometheus.CounterVec {
+		return prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: cfg.namespace,
+			Subsystem: "retry",
+			Name:      name,
+			Help:      help,
+		}, labels)
+	}
+	gauge := func(name, help string) *prometheus.GaugeVec {
+		return prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: cfg.namespace,
+			Subsystem: "retry",
+			Name:      name,
+			Help:      help,
+		}, labels)
+	}
+	histogram := func(name, help string) *prometheus.HistogramVec {
+		return prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: cfg.namespace,
+			Subsystem: "retry",
+			Name:      name,
+			Help:      help,
+			Buckets:   cfg.buckets,
+		}, labels)
+	}
+
+	return &Collector{
+		cfg:             cfg,
+		attempts:        counter("attempts_total", "Total number of attempts made."),
+		retries:         counter("retries_total", "Total number of retries scheduled after a failed attempt."),
+		successes:       counter("successes_total", "Total number of calls that eventually succeeded."),
+		retriedSuccess:  counter("successes_after_retry_total", "Total number of calls that succeeded after at least one retry."),
+		exhausted:       counter("exhausted_total", "Total number of calls that ran out of attempts."),
+		backoff:         histogram("backoff_seconds", "Wait before each retry."),
+		attemptDuration: histogram("attempt_duration_seconds", "Duration of each attempt."),
+		toSuccess: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: cfg.namespace,
+			Subsystem: "retry",
+			Name:      "attempts_to_success",
+			Help:      "Number of attempts successful calls needed.",
+			Buckets:   prometheus.LinearBuckets(1, 1, 10),
+		}, labels),
+		inFlight: gauge("in_flight", "Number of calls in progress."),
+		sleeping: gauge("sleeping", "Number of calls waiting between attempts."),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.attempts.Describe(ch)
+	c.retries.Describe(ch)
+	c.successes.Describe(ch)
+	c.retriedSuccess.Describe(ch)
+	c.exhausted.Describe(ch)
+	c.backoff.Describe(ch)
+	c.attemptDuration.Describe(ch)
+	c.toSuccess.Describe(ch)
+	c.inFlight.Describe(ch)
+	c.sleeping.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.attempts.Collect(ch)
+	c.retries.Collect(ch)
+	c.successes.Collect(ch)
+	c.retriedSuccess.Collect(ch)
+	c.exhausted.Collect(ch)
+	c.backoff.Collect(ch)
+	c.attemptDuration.Collect(ch)
+	c.toSuccess.Collect(ch)
+	c.inFlight.Collect(ch)
+	c.sleeping.Collect(ch)
+}
+
+var _ retry.MetricsProvider = (*Collector)(nil)
+
+// Metrics returns a retry.Metrics recording into the collector under the
+// given retrier name.
+func (c *Collector) Metrics(name string) retry.Metrics {
+	values := c.cfg.labelValues(name)
+	return &metrics{
+		attempts:        c.attempts.WithLabelValues(values...),
+		retries:         c.retries.WithLabelValues(values...),
+		successes:       c.successes.WithLabelValues(values...),
+		retriedSuccess:  c.retriedSuccess.WithLabelValues(values...),
+		exhausted:       c.exhausted.WithLabelValues(values...),
+		backoff:         c.backoff.WithLabelValues(values...),
+		attemptDuration: c.attemptDuration.WithLabelValues(values...),
+		toSuccess:       c.toSuccess.WithLabelValues(values...),
+		inFlight:        c.inFlight.WithLabelValues(values...),
+		sleeping:        c.sleeping.WithLabelValues(values...),
+	}
+}
+
+type metrics struct {
+	attempts        prometheus.Counter
+	retries         prometheus.Counter
+	successes       prometheus.Counter
+	retriedSuccess  prometheus.Counter
+	exhausted       prometheus.Counter
+	backoff         prometheus.Observer
+	attemptDuration prometheus.Observer
+	toSuccess       prometheus.Observer
+	inFlight        prometheus.Gauge
+	sleeping        prometheus.Gauge
+}
+
+func (m *metrics) IncAttempt()                            { m.attempts.Inc() }
+func (m *metrics) IncRetry()                              { m.retries.Inc() }
+func (m *metrics) IncSuccess()                            { m.successes.Inc() }
+func (m *metrics) IncSuccessAfterRetry()                  { m.retriedSuccess.Inc() }
+func (m *metrics) IncExhausted()                          { m.exhausted.Inc() }
+func (m *metrics) ObserveDelay(d time.Duration)           { m.backoff.Observe(d.Seconds()) }
+func (m *metrics) ObserveAttemptDuration(d time.Duration) { m.attemptDuration.Observe(d.Seconds()) }
+func (m *metrics) ObserveAttemptsToSuccess(n int)         { m.toSuccess.Observe(float64(n)) }
+func (m *metrics) AddInFlight(delta int)                  { m.inFlight.Add(float64(delta)) }
+func (m *metrics) AddSleeping(delta int)                  { m.sleeping.Add(float64(delta)) }
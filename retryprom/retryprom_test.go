@@ -0,0 +1,93 @@
+package retryprom
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/er-davo/retry"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollector(t *testing.T) {
+	c := NewCollector(WithNamespace("app"))
+	reg := prometheus.NewPedanticRegistry()
+	require.NoError(t, reg.Register(c))
+
+	r := retry.New(
+		retry.WithMaxAttempts(3),
+		retry.WithBackoff(retry.FixedBackoff{Interval: time.Millisecond}),
+		retry.WithMetrics(c.Metrics("payments")),
+	)
+
+	_ = r.Do(context.Background(), func(attempt int) error {
+		return errors.New("boom")
+	})
+
+	expected := `
+# HELP app_retry_attempts_total Total number of attempts made.
+# TYPE app_retry_attempts_total counter
+app_retry_attempts_total{retrier="payments"} 3
+# HELP app_retry_exhausted_total Total number of calls that ran out of attempts.
+# TYPE app_retry_exhausted_total counter
+app_retry_exhausted_total{retrier="payments"} 1
+# HELP app_retry_in_flight Number of calls in progress.
+# TYPE app_retry_in_flight gauge
+app_retry_in_flight{retrier="payments"} 0
+# HELP app_retry_retries_total Total number of retries scheduled after a failed attempt.
+# TYPE app_retry_retries_total counter
+app_retry_retries_total{retrier="payments"} 2
+`
+	assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected),
+		"app_retry_attempts_total", "app_retry_exhausted_total", "app_retry_in_flight", "app_retry_retries_total"))
+
+	count, err := testutil.GatherAndCount(reg, "app_retry_backoff_seconds", "app_retry_attempt_duration_seconds")
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	_ = r.Do(context.Background(), func(attempt int) error { return nil })
+	count, err = testutil.GatherAndCount(reg, "app_retry_attempts_to_success")
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func TestWithoutRetrierLabel(t *testing.T) {
+	c := NewCollector(WithoutRetrierLabel())
+	reg := prometheus.NewPedanticRegistry()
+	require.NoError(t, reg.Register(c))
+
+	c.Metrics("a").IncAttempt()
+	c.Metrics("b").IncAttempt()
+
+	expected := `
+# HELP retry_attempts_total Total number of attempts made.
+# TYPE retry_attempts_total counter
+retry_attempts_total 2
+`
+	assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected), "retry_attempts_total"))
+}
+
+func TestWithHashedNames(t *testing.T) {
+	c := NewCollector(WithHashedNames(4))
+	reg := prometheus.NewPedanticRegistry()
+	require.NoError(t, reg.Register(c))
+
+	for i := range 100 {
+		c.Metrics("host-" + strconv.Itoa(i)).IncAttempt()
+	}
+
+	count, err := testutil.GatherAndCount(reg, "retry_attempts_total")
+	require.NoError(t, err)
+	assert.LessOrEqual(t, count, 4)
+
+	values := c.cfg.labelValues("host-0")
+	require.Len(t, values, 1)
+	assert.Regexp(t, `^h[0-3]$`, values[0])
+	assert.Equal(t, values, c.cfg.labelValues("host-0"))
+}
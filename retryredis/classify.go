@@ -0,0 +1,53 @@
+// Package retryredis integrates the retry package with Redis clients.
+package retryredis
+
+import (
+	"errors"
+	"strings"
+)
+
+// IsTransient reports whether err is a transient Redis condition worth
+// retrying:
+//   - LOADING, the server is loading its dataset into memory
+//   - CLUSTERDOWN, TRYAGAIN and MASTERDOWN during cluster reconfiguration
+//   - MOVED and ASK slot redirections
+//   - READONLY, a write reached a replica during failover
+//   - connection pool timeouts and exhaustion
+//
+// It recognizes both go-redis and redigo error shapes by message, so
+// neither client needs to be imported. Network failures are not covered;
+// combine with retry.IsNetworkTransient via retry.AnyOf for that.
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		msg := e.Error()
+		if isTransientReply(msg) || isPoolExhausted(msg) {
+			return true
+		}
+	}
+	return false
+}
+
+// isTransientReply reports whether msg is a Redis error reply whose
+// prefix denotes a transient condition.
+func isTransientReply(msg string) bool {
+	prefix, _, _ := strings.Cut(msg, " ")
+	switch prefix {
+	case "LOADING", "CLUSTERDOWN", "TRYAGAIN", "MASTERDOWN", "MOVED", "ASK", "READONLY":
+		return true
+	}
+	return false
+}
+
+// isPoolExhausted reports whether msg is a client-side pool error.
+func isPoolExhausted(msg string) bool {
+	switch msg {
+	case "redis: connection pool timeout", // go-redis
+		"redigo: connection pool exhausted": // redigo
+		return true
+	}
+	return false
+}
@@ -0,0 +1,40 @@
+package retryredis
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// redisError mirrors the string-based reply errors of go-redis and redigo.
+type redisError string
+
+func (e redisError) Error() string { return string(e) }
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"loading", redisError("LOADING Redis is loading the dataset in memory"), true},
+		{"clusterdown", redisError("CLUSTERDOWN The cluster is down"), true},
+		{"tryagain wrapped", fmt.Errorf("mget: %w", redisError("TRYAGAIN Multiple keys request during rehashing of slot")), true},
+		{"moved", redisError("MOVED 3999 127.0.0.1:6381"), true},
+		{"ask", redisError("ASK 3999 127.0.0.1:6381"), true},
+		{"readonly", redisError("READONLY You can't write against a read only replica."), true},
+		{"go-redis pool timeout", errors.New("redis: connection pool timeout"), true},
+		{"redigo pool exhausted", fmt.Errorf("get: %w", errors.New("redigo: connection pool exhausted")), true},
+		{"wrong type", redisError("WRONGTYPE Operation against a key holding the wrong kind of value"), false},
+		{"nil reply", errors.New("redis: nil"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsTransient(tt.err))
+		})
+	}
+}
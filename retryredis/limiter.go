@@ -0,0 +1,76 @@
+package retryredis
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/er-davo/retry"
+)
+
+// Store is the storage shared by the instances of a fleet. A Redis client
+// implements it with INCRBY followed by EXPIRE NX in a pipeline:
+//
+//	func (s store) IncrBy(ctx context.Context, key string, n int64, ttl time.Duration) (int64, error) {
+//		var incr *redis.IntCmd
+//		_, err := s.rdb.TxPipelined(ctx, func(p redis.Pipeliner) error {
+//			incr = p.IncrBy(ctx, key, n)
+//			p.ExpireNX(ctx, key, ttl)
+//			return nil
+//		})
+//		return incr.Val(), err
+//	}
+type Store interface {
+	// IncrBy atomically adds n to the counter at key, creating it with a
+	// time to live of ttl if it does not exist, and returns the new value.
+	IncrBy(ctx context.Context, key string, n int64, ttl time.Duration) (int64, error)
+}
+
+// RetryLimiter caps the retries made by every instance of a fleet toward a
+// dependency, e.g. a partner API, to a shared number per window, counted
+// in a Store under a key agreed on by the instances. It implements
+// retry.SLOGate and is installed with WithRetryLimiter.
+//
+// Windows are fixed, so a burst may reach twice the limit around a window
+// boundary. If the store fails, retries are allowed, so an outage of the
+// store does not disable retries.
+type RetryLimiter struct {
+	store  Store
+	key    string
+	limit  int64
+	window time.Duration
+	now    func() time.Time
+}
+
+// NewRetryLimiter creates a RetryLimiter allowing limit retries per window
+// across the instances sharing store and key.
+func NewRetryLimiter(store Store, key string, limit int64, window time.Duration) *RetryLimiter {
+	return &RetryLimiter{
+		store:  store,
+		key:    key,
+		limit:  limit,
+		window: max(window, time.Millisecond),
+		now:    time.Now,
+	}
+}
+
+// AllowRetry counts a retry in the current window and reports whether the
+// window still had room for it.
+func (l *RetryLimiter) AllowRetry(ctx context.Context) bool {
+	window := l.now().UnixNano() / int64(l.window)
+	key := l.key + ":" + strconv.FormatInt(window, 10)
+	n, err := l.store.IncrBy(ctx, key, 1, l.window)
+	if err != nil {
+		return true
+	}
+	return n <= l.limit
+}
+
+// WithRetryLimiter makes the retrier spend l on every retry. Once the
+// fleet has exhausted the window, Do stops at the first failed attempt and
+// returns a retry.BudgetExceededError. l is consulted after the local
+// budget and quota of the retrier, so retries they veto are not counted;
+// it should also come after any other retry.SLOGate for the same reason.
+func WithRetryLimiter(l *RetryLimiter) retry.RetryOption {
+	return retry.WithSLOGate(l)
+}
@@ -0,0 +1,95 @@
+package retryredis
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/er-davo/retry"
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryStore is an in-memory Store ignoring expiry.
+type memoryStore struct {
+	mu       sync.Mutex
+	counters map[string]int64
+	err      error
+}
+
+func (s *memoryStore) IncrBy(_ context.Context, key string, n int64, _ time.Duration) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return 0, s.err
+	}
+	if s.counters == nil {
+		s.counters = make(map[string]int64)
+	}
+	s.counters[key] += n
+	return s.counters[key], nil
+}
+
+func TestRetryLimiter(t *testing.T) {
+	store := &memoryStore{}
+	now := time.Unix(0, 0)
+	newLimiter := func() *RetryLimiter {
+		l := NewRetryLimiter(store, "partner-api", 2, time.Minute)
+		l.now = func() time.Time { return now }
+		return l
+	}
+	a, b := newLimiter(), newLimiter()
+
+	assert.True(t, a.AllowRetry(context.Background()))
+	assert.True(t, b.AllowRetry(context.Background()))
+	assert.False(t, a.AllowRetry(context.Background()), "the limit is shared by the instances")
+
+	now = now.Add(time.Minute)
+	assert.True(t, b.AllowRetry(context.Background()), "a new window starts")
+
+	store.err = errors.New("connection refused")
+	assert.True(t, a.AllowRetry(context.Background()), "store failures allow retries")
+}
+
+func TestWithRetryLimiter(t *testing.T) {
+	errFail := errors.New("fail")
+	r := retry.New(
+		retry.WithMaxAttempts(5),
+		retry.WithBackoff(retry.FixedBackoff{}),
+		WithRetryLimiter(NewRetryLimiter(&memoryStore{}, "k", 2, time.Hour)),
+	)
+
+	calls := 0
+	err := r.Do(context.Background(), func(int) error {
+		calls++
+		return errFail
+	})
+	assert.ErrorIs(t, err, retry.ErrBudgetExceeded)
+	assert.ErrorIs(t, err, errFail)
+	assert.Equal(t, 3, calls)
+}
+
+func TestWithRetryLimiter_LocalQuota(t *testing.T) {
+	store := &memoryStore{}
+	r := retry.New(
+		retry.WithMaxAttempts(5),
+		retry.WithBackoff(retry.FixedBackoff{}),
+		retry.WithRetryQuota(1, time.Hour),
+		WithRetryLimiter(NewRetryLimiter(store, "k", 10, time.Hour)),
+	)
+
+	calls := 0
+	err := r.Do(context.Background(), func(int) error {
+		calls++
+		return errors.New("fail")
+	})
+	assert.ErrorIs(t, err, retry.ErrBudgetExceeded)
+	assert.Equal(t, 2, calls)
+
+	var counted int64
+	for _, n := range store.counters {
+		counted += n
+	}
+	assert.Equal(t, int64(1), counted, "retries vetoed by the quota are not counted by the fleet")
+}
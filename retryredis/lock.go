@@ -0,0 +1,92 @@
+package retryredis
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+)
+
+// lockPollInterval is how often Lock tries again to acquire a lock held by
+// another process.
+const lockPollInterval = 50 * time.Millisecond
+
+// LockClient is the subset of a Redis client used by Lock. A go-redis
+// client implements SetNX with SetNX(...).Result() and DeleteIfEqual with
+// the script
+//
+//	if redis.call("GET", KEYS[1]) == ARGV[1] then
+//		return redis.call("DEL", KEYS[1])
+//	end
+//	return 0
+type LockClient interface {
+	// SetNX sets key to value with a time to live of ttl if key does not
+	// exist, and reports whether it did.
+	SetNX(ctx context.Context, key, value string, ttl time.Duration) (bool, error)
+	// DeleteIfEqual deletes key if its value is value.
+	DeleteIfEqual(ctx context.Context, key, value string) error
+}
+
+// Lock is a retry.Locker backed by a Redis key, for use with
+// retry.WithAttemptLock. The key expires after the TTL, so a crashed holder
+// cannot keep it forever; the TTL must exceed the duration of an attempt.
+// Goroutines of the same process sharing a Lock also exclude each other.
+type Lock struct {
+	client LockClient
+	key    string
+	ttl    time.Duration
+	token  string
+	local  chan struct{}
+}
+
+// NewLock creates a Lock on key with the given TTL.
+func NewLock(client LockClient, key string, ttl time.Duration) *Lock {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return &Lock{
+		client: client,
+		key:    key,
+		ttl:    ttl,
+		token:  hex.EncodeToString(b[:]),
+		local:  make(chan struct{}, 1),
+	}
+}
+
+// Lock blocks until the lock is held or ctx is done.
+func (l *Lock) Lock(ctx context.Context) error {
+	select {
+	case l.local <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	ticker := time.NewTicker(lockPollInterval)
+	defer ticker.Stop()
+	for {
+		ok, err := l.client.SetNX(ctx, l.key, l.token, l.ttl)
+		if err != nil || ok {
+			if err != nil {
+				<-l.local
+			}
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			<-l.local
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Unlock releases the lock. The key is only deleted if it still holds the
+// token of l, so a holder whose key expired cannot release the lock of the
+// next one. Unlocking a Lock that is not held is a no-op.
+func (l *Lock) Unlock(ctx context.Context) error {
+	select {
+	case <-l.local:
+	default:
+		return nil
+	}
+	return l.client.DeleteIfEqual(ctx, l.key, l.token)
+}
@@ -0,0 +1,63 @@
+package retryredis
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/er-davo/retry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryLockClient is an in-memory LockClient ignoring expiry.
+type memoryLockClient struct {
+	mu   sync.Mutex
+	keys map[string]string
+}
+
+func (c *memoryLockClient) SetNX(_ context.Context, key, value string, _ time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.keys[key]; ok {
+		return false, nil
+	}
+	if c.keys == nil {
+		c.keys = make(map[string]string)
+	}
+	c.keys[key] = value
+	return true, nil
+}
+
+func (c *memoryLockClient) DeleteIfEqual(_ context.Context, key, value string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.keys[key] == value {
+		delete(c.keys, key)
+	}
+	return nil
+}
+
+var _ retry.Locker = (*Lock)(nil)
+
+func TestLock(t *testing.T) {
+	client := &memoryLockClient{}
+	a := NewLock(client, "job", time.Minute)
+	b := NewLock(client, "job", time.Minute)
+
+	require.NoError(t, a.Lock(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*lockPollInterval)
+	defer cancel()
+	assert.ErrorIs(t, b.Lock(ctx), context.DeadlineExceeded, "another process holds the lock")
+
+	require.NoError(t, b.Unlock(context.Background()))
+	assert.Contains(t, client.keys, "job", "unlocking a lock that is not held is a no-op")
+	require.NoError(t, a.Unlock(context.Background()))
+
+	require.NoError(t, b.Lock(context.Background()))
+	client.keys["job"] = "next holder"
+	require.NoError(t, b.Unlock(context.Background()))
+	assert.Contains(t, client.keys, "job", "an expired holder cannot release the lock of the next one")
+}
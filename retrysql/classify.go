@@ -0,0 +1,68 @@
+// Package retrysql integrates the retry package with database/sql.
+package retrysql
+
+import (
+	"database/sql/driver"
+	"errors"
+	"regexp"
+	"slices"
+)
+
+// sqlStater is implemented by driver errors exposing an SQLSTATE code,
+// such as *pgconn.PgError (pgx) and *pq.Error (lib/pq).
+type sqlStater interface {
+	SQLState() string
+}
+
+// IsTransient reports whether err is a transient database failure that is
+// safe to retry by re-running the statement or transaction:
+//   - a broken connection (driver.ErrBadConn)
+//   - a serialization failure or deadlock
+//   - lock wait timeouts and busy databases
+//   - connection limits being exhausted
+//
+// Drivers exposing a MySQL error number or an SQLSTATE code are
+// classified by that code. MySQL errors with another number, such as 1040
+// too many connections, and other drivers are recognized by their
+// well-known error messages.
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, driver.ErrBadConn) {
+		return true
+	}
+
+	if n, ok := mysqlNumber(err); ok && slices.Contains(defaultMySQLNumbers(), n) {
+		return true
+	}
+	if code, ok := sqlState(err); ok {
+		return slices.Contains(transientSQLStates(), code)
+	}
+
+	return transientMessage.MatchString(err.Error())
+}
+
+// transientSQLStates returns the SQLSTATE codes considered transient.
+func transientSQLStates() []string {
+	return []string{
+		"40001", // serialization_failure
+		"40P01", // deadlock_detected
+		"53300", // too_many_connections
+		"08000", // connection_exception
+		"08003", // connection_does_not_exist
+		"08006", // connection_failure
+		"55P03", // lock_not_available
+		"57P01", // admin_shutdown
+	}
+}
+
+// transientMessage matches error messages of drivers that expose neither
+// an SQLSTATE nor a stable error type.
+var transientMessage = regexp.MustCompile(`(?i)` +
+	`deadlock|` +
+	`serializ(e|ation) (access|failure)|` +
+	`lock wait timeout|` +
+	`database (table )?is locked|` +
+	`too many connections|` +
+	`connection pool (exhausted|timeout)`)
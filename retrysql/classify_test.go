@@ -0,0 +1,46 @@
+package retrysql
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errCustom = errors.New("custom error")
+
+type stateError string
+
+func (e stateError) Error() string    { return "sqlstate " + string(e) }
+func (e stateError) SQLState() string { return string(e) }
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"bad conn", fmt.Errorf("query: %w", driver.ErrBadConn), true},
+		{"serialization failure", stateError("40001"), true},
+		{"deadlock", fmt.Errorf("exec: %w", stateError("40P01")), true},
+		{"unique violation", stateError("23505"), false},
+		{"mysql deadlock message", errors.New("Error 1213 (40001): Deadlock found when trying to get lock"), true},
+		{"mysql lock wait", errors.New("Error 1205 (HY000): Lock wait timeout exceeded"), true},
+		{"sqlite busy", errors.New("database is locked"), true},
+		{"pool exhausted", errors.New("acquire: connection pool timeout"), true},
+		{"mysql deadlock number", &mysqlError{Number: 1213, Message: "Deadlock found"}, true},
+		{"mysql too many connections", &mysqlError{Number: 1040, Message: "Too many connections"}, true},
+		{"mysql duplicate entry", &mysqlError{Number: 1062, Message: "Duplicate entry '1' for key 'PRIMARY'"}, false},
+		{"no rows", sql.ErrNoRows, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsTransient(tt.err))
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package retrysql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/er-davo/retry"
+)
+
+// DB wraps a *sql.DB, retrying its statements with a retrier.
+type DB struct {
+	db      *sql.DB
+	retrier retry.ContextRetrier
+}
+
+// Wrap returns a DB running the statements of db with r. A nil r retries
+// the errors of IsTransient with the default attempts and backoff.
+//
+// Every retried statement runs again from the start, so statements run
+// with ExecContext must be idempotent: a broken connection may hide a
+// statement that succeeded. Queries within transactions are not retried;
+// see InTx to retry a whole transaction.
+func Wrap(db *sql.DB, r retry.Retrier) *DB {
+	if r == nil {
+		r = retry.New(retry.WithIsRetryableFunc(IsTransient))
+	}
+	return &DB{db: db, retrier: retry.AsContextRetrier(r)}
+}
+
+// DB returns the wrapped *sql.DB.
+func (db *DB) DB() *sql.DB { return db.db }
+
+// QueryContext runs a query returning rows, retrying it until it succeeds.
+// Errors met while iterating the rows are not retried.
+func (db *DB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	var rows *sql.Rows
+	err := db.retrier.DoContext(ctx, func(context.Context, int) error {
+		// The rows outlive the attempt: they get the context of the call,
+		// not the one of the attempt, canceled when it returns.
+		var err error
+		rows, err = db.db.QueryContext(ctx, query, args...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// ExecContext runs a statement without returning rows, retrying it until
+// it succeeds.
+func (db *DB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	var res sql.Result
+	err := db.retrier.DoContext(ctx, func(ctx context.Context, _ int) error {
+		var err error
+		res, err = db.db.ExecContext(ctx, query, args...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// PingContext verifies the connection to the database, retrying until it
+// succeeds.
+func (db *DB) PingContext(ctx context.Context) error {
+	return db.retrier.DoContext(ctx, func(ctx context.Context, _ int) error {
+		return db.db.PingContext(ctx)
+	})
+}
@@ -0,0 +1,169 @@
+package retrysql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/er-davo/retry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeConnector is a database/sql driver logging the statements it runs,
+// failing them as fail decides.
+type fakeConnector struct {
+	mu   sync.Mutex
+	log  []string
+	fail func(query string, n int) error
+}
+
+func openFake(t *testing.T, fail func(query string, n int) error) (*sql.DB, *fakeConnector) {
+	c := &fakeConnector{fail: fail}
+	db := sql.OpenDB(c)
+	t.Cleanup(func() { db.Close() })
+	return db, c
+}
+
+// run logs query, returning the error of fail for its n-th run.
+func (c *fakeConnector) run(query string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := 0
+	for _, q := range c.log {
+		if q == query {
+			n++
+		}
+	}
+	c.log = append(c.log, query)
+	if c.fail == nil {
+		return nil
+	}
+	return c.fail(query, n)
+}
+
+func (c *fakeConnector) statements() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.log...)
+}
+
+func (c *fakeConnector) Connect(context.Context) (driver.Conn, error) { return fakeConn{c}, nil }
+func (c *fakeConnector) Driver() driver.Driver                        { return fakeDriver{c} }
+
+type fakeDriver struct{ c *fakeConnector }
+
+func (d fakeDriver) Open(string) (driver.Conn, error) { return fakeConn(d), nil }
+
+type fakeConn struct{ c *fakeConnector }
+
+func (c fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c fakeConn) Close() error                        { return nil }
+
+func (c fakeConn) Begin() (driver.Tx, error) {
+	if err := c.c.run("BEGIN"); err != nil {
+		return nil, err
+	}
+	return fakeTx(c), nil
+}
+
+func (c fakeConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	if err := c.c.run(query); err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (c fakeConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	if err := c.c.run(query); err != nil {
+		return nil, err
+	}
+	return &fakeRows{}, nil
+}
+
+func (c fakeConn) Ping(context.Context) error { return c.c.run("PING") }
+
+type fakeTx struct{ c *fakeConnector }
+
+func (tx fakeTx) Commit() error   { return tx.c.run("COMMIT") }
+func (tx fakeTx) Rollback() error { return tx.c.run("ROLLBACK") }
+
+// fakeRows is a single row with the value 1.
+type fakeRows struct{ done bool }
+
+func (r *fakeRows) Columns() []string { return []string{"n"} }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = int64(1)
+	return nil
+}
+
+// failFirst fails the first run of query with err.
+func failFirst(query string, err error) func(string, int) error {
+	return func(q string, n int) error {
+		if q == query && n == 0 {
+			return err
+		}
+		return nil
+	}
+}
+
+func testRetrier() retry.ContextRetrier {
+	return retry.New(
+		retry.WithMaxAttempts(3),
+		retry.WithBackoff(retry.FixedBackoff{Interval: time.Millisecond}),
+		retry.WithIsRetryableFunc(IsTransient),
+	)
+}
+
+func TestWrap(t *testing.T) {
+	ctx := context.Background()
+	db, c := openFake(t, func(q string, n int) error {
+		if n == 0 {
+			return stateError("40P01")
+		}
+		return nil
+	})
+	w := Wrap(db, testRetrier())
+	assert.Same(t, db, w.DB())
+
+	require.NoError(t, w.PingContext(ctx))
+
+	res, err := w.ExecContext(ctx, "UPDATE t SET n = 1")
+	require.NoError(t, err)
+	affected, _ := res.RowsAffected()
+	assert.Equal(t, int64(1), affected)
+
+	rows, err := w.QueryContext(ctx, "SELECT n FROM t")
+	require.NoError(t, err)
+	defer rows.Close()
+	require.True(t, rows.Next())
+	var n int
+	require.NoError(t, rows.Scan(&n))
+	assert.Equal(t, 1, n)
+
+	assert.Equal(t, []string{
+		"PING", "PING",
+		"UPDATE t SET n = 1", "UPDATE t SET n = 1",
+		"SELECT n FROM t", "SELECT n FROM t",
+	}, c.statements())
+}
+
+func TestWrap_NotTransient(t *testing.T) {
+	db, c := openFake(t, failFirst("INSERT", stateError("23505")))
+	_, err := Wrap(db, nil).ExecContext(context.Background(), "INSERT")
+
+	var se stateError
+	assert.ErrorAs(t, err, &se)
+	assert.Equal(t, []string{"INSERT"}, c.statements(), "only transient errors are retried")
+}
@@ -0,0 +1,85 @@
+package retrysql
+
+import (
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/er-davo/retry"
+)
+
+// IsMySQLTransient reports whether err is a MySQL error with one of the
+// default retryable error numbers (see MySQLClassifier).
+func IsMySQLTransient(err error) bool {
+	return MySQLClassifier()(err)
+}
+
+// MySQLClassifier returns a retry.IsRetryableFunc that retries MySQL errors
+// whose error number is one of numbers.
+//
+// It works with go-sql-driver/mysql (*mysql.MySQLError) without importing
+// the driver. Lost connections are also retried when the driver reports
+// them as driver.ErrBadConn or mysql.ErrInvalidConn.
+//
+// With no numbers it retries:
+//   - 1213 ER_LOCK_DEADLOCK
+//   - 1205 ER_LOCK_WAIT_TIMEOUT
+//   - 2006 CR_SERVER_GONE_ERROR
+//   - 2013 CR_SERVER_LOST
+func MySQLClassifier(numbers ...uint16) retry.IsRetryableFunc {
+	if len(numbers) == 0 {
+		numbers = defaultMySQLNumbers()
+	}
+
+	return func(err error) bool {
+		if err == nil {
+			return false
+		}
+		if n, ok := mysqlNumber(err); ok {
+			return slices.Contains(numbers, n)
+		}
+		return errors.Is(err, driver.ErrBadConn) || isInvalidConn(err)
+	}
+}
+
+// defaultMySQLNumbers returns the MySQL error numbers retried by default.
+func defaultMySQLNumbers() []uint16 {
+	return []uint16{
+		1213, // ER_LOCK_DEADLOCK
+		1205, // ER_LOCK_WAIT_TIMEOUT
+		2006, // CR_SERVER_GONE_ERROR
+		2013, // CR_SERVER_LOST
+	}
+}
+
+// mysqlNumber extracts a MySQL error number from err's chain by looking
+// for an unsigned integer field named Number, which is how
+// *mysql.MySQLError exposes it.
+func mysqlNumber(err error) (uint16, bool) {
+	var (
+		found uint16
+		ok    bool
+	)
+	walk(err, func(e error) bool {
+		f, has := field(e, "Number")
+		if !has {
+			return false
+		}
+		switch f.Kind() {
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			found, ok = uint16(f.Uint()), true
+		}
+		return ok
+	})
+	return found, ok
+}
+
+// isInvalidConn reports whether err matches mysql.ErrInvalidConn,
+// which the driver returns when the server closed the connection.
+func isInvalidConn(err error) bool {
+	return walk(err, func(e error) bool {
+		return strings.HasSuffix(e.Error(), "invalid connection")
+	})
+}
@@ -0,0 +1,48 @@
+package retrysql
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// mysqlError mirrors the shape of *mysql.MySQLError.
+type mysqlError struct {
+	Number   uint16
+	SQLState [5]byte
+	Message  string
+}
+
+func (e *mysqlError) Error() string { return fmt.Sprintf("Error %d: %s", e.Number, e.Message) }
+
+func TestMySQLClassifier(t *testing.T) {
+	tests := []struct {
+		name     string
+		classify func(error) bool
+		err      error
+		want     bool
+	}{
+		{"deadlock", IsMySQLTransient, &mysqlError{Number: 1213, Message: "Deadlock found"}, true},
+		{"lock wait timeout", IsMySQLTransient, fmt.Errorf("exec: %w", &mysqlError{Number: 1205}), true},
+		{"server gone", IsMySQLTransient, &mysqlError{Number: 2006}, true},
+		{"duplicate entry", IsMySQLTransient, &mysqlError{Number: 1062}, false},
+		{"bad conn", IsMySQLTransient, driver.ErrBadConn, true},
+		{"invalid connection", IsMySQLTransient, fmt.Errorf("query: %w", errors.New("invalid connection")), true},
+		{"custom numbers", MySQLClassifier(1062), &mysqlError{Number: 1062}, true},
+		{"custom excludes default", MySQLClassifier(1062), &mysqlError{Number: 1213}, false},
+		{"plain error", IsMySQLTransient, errCustom, false},
+		{"nil", IsMySQLTransient, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.classify(tt.err))
+		})
+	}
+
+	assert.True(t, IsTransient(&mysqlError{Number: 1213}))
+	assert.False(t, IsTransient(&mysqlError{Number: 1062, Message: "Duplicate entry"}))
+}
@@ -0,0 +1,116 @@
+package retrysql
+
+import (
+	"reflect"
+	"slices"
+
+	"github.com/er-davo/retry"
+)
+
+// IsPostgresTransient reports whether err is a PostgreSQL error with one of
+// the default retryable SQLSTATE codes (see PostgresClassifier).
+func IsPostgresTransient(err error) bool {
+	return PostgresClassifier()(err)
+}
+
+// PostgresClassifier returns a retry.IsRetryableFunc that retries
+// PostgreSQL errors whose SQLSTATE is one of codes.
+//
+// It works with both pgx (*pgconn.PgError) and lib/pq (*pq.Error) without
+// importing either driver. Errors without an SQLSTATE are not retried.
+//
+// With no codes it retries:
+//   - 40001 serialization_failure
+//   - 40P01 deadlock_detected
+//   - 55P03 lock_not_available
+//   - 57P01 admin_shutdown
+func PostgresClassifier(codes ...string) retry.IsRetryableFunc {
+	if len(codes) == 0 {
+		codes = defaultPostgresCodes()
+	}
+
+	return func(err error) bool {
+		code, ok := sqlState(err)
+		return ok && slices.Contains(codes, code)
+	}
+}
+
+// defaultPostgresCodes returns the SQLSTATE codes retried by default.
+func defaultPostgresCodes() []string {
+	return []string{
+		"40001", // serialization_failure
+		"40P01", // deadlock_detected
+		"55P03", // lock_not_available
+		"57P01", // admin_shutdown
+	}
+}
+
+// sqlState extracts an SQLSTATE code from err's chain.
+//
+// It prefers the SQLState method (pgx, newer lib/pq) and falls back to a
+// five-character string field named Code, which is how lib/pq exposes it.
+func sqlState(err error) (string, bool) {
+	var found string
+	walk(err, func(e error) bool {
+		if s, ok := e.(sqlStater); ok {
+			found = s.SQLState()
+			return true
+		}
+		if code, ok := stringField(e, "Code"); ok && len(code) == 5 {
+			found = code
+			return true
+		}
+		return false
+	})
+	return found, found != ""
+}
+
+// walk calls fn for every error in err's tree in pre-order until fn
+// returns true.
+func walk(err error, fn func(error) bool) bool {
+	if err == nil {
+		return false
+	}
+	if fn(err) {
+		return true
+	}
+
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		return walk(u.Unwrap(), fn)
+	case interface{ Unwrap() []error }:
+		for _, e := range u.Unwrap() {
+			if walk(e, fn) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// stringField returns the value of the string-kinded field name of the
+// struct err (or points to).
+func stringField(err error, name string) (string, bool) {
+	f, ok := field(err, name)
+	if !ok || f.Kind() != reflect.String {
+		return "", false
+	}
+	return f.String(), true
+}
+
+// field returns the field name of the struct err (or points to).
+func field(err error, name string) (reflect.Value, bool) {
+	v := reflect.ValueOf(err)
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}, false
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+
+	f := v.FieldByName(name)
+	return f, f.IsValid()
+}
@@ -0,0 +1,46 @@
+package retrysql
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type pqErrorCode string
+
+// pqError mirrors the shape of *pq.Error, which exposes the SQLSTATE
+// through a string-typed Code field.
+type pqError struct {
+	Severity string
+	Code     pqErrorCode
+	Message  string
+}
+
+func (e *pqError) Error() string { return "pq: " + e.Message }
+
+func TestPostgresClassifier(t *testing.T) {
+	tests := []struct {
+		name     string
+		classify func(error) bool
+		err      error
+		want     bool
+	}{
+		{"pgx serialization failure", IsPostgresTransient, stateError("40001"), true},
+		{"pgx lock not available", IsPostgresTransient, fmt.Errorf("update: %w", stateError("55P03")), true},
+		{"pq deadlock", IsPostgresTransient, &pqError{Code: "40P01", Message: "deadlock detected"}, true},
+		{"pq admin shutdown joined", IsPostgresTransient, errors.Join(errCustom, &pqError{Code: "57P01"}), true},
+		{"pq unique violation", IsPostgresTransient, &pqError{Code: "23505"}, false},
+		{"custom codes", PostgresClassifier("23505"), &pqError{Code: "23505"}, true},
+		{"custom excludes default", PostgresClassifier("23505"), stateError("40001"), false},
+		{"no sqlstate", IsPostgresTransient, errCustom, false},
+		{"nil", IsPostgresTransient, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.classify(tt.err))
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package retrysql
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"slices"
+
+	"github.com/er-davo/retry"
+)
+
+// IsTxConflict reports whether err is a serialization failure or a
+// deadlock: the database rolled the transaction back because it conflicted
+// with a concurrent one, and running it again is expected to succeed.
+func IsTxConflict(err error) bool {
+	if err == nil {
+		return false
+	}
+	if n, ok := mysqlNumber(err); ok {
+		return n == 1213 || n == 1205 // ER_LOCK_DEADLOCK, ER_LOCK_WAIT_TIMEOUT
+	}
+	if code, ok := sqlState(err); ok {
+		return slices.Contains([]string{"40001", "40P01"}, code)
+	}
+	return txConflictMessage.MatchString(err.Error())
+}
+
+// txConflictMessage matches the conflict messages of drivers that expose
+// neither an SQLSTATE nor a stable error type.
+var txConflictMessage = regexp.MustCompile(`(?i)` +
+	`deadlock|` +
+	`serializ(e|ation) (access|failure)|` +
+	`lock wait timeout`)
+
+// InTx runs fn in a transaction of db and commits it, running the whole
+// transaction again with r when it fails: a transaction whose statements
+// or commit fail on a serialization failure or deadlock must be retried
+// from its beginning, not statement by statement. A nil r retries the
+// errors of IsTxConflict with the default attempts and backoff.
+//
+// The transaction is rolled back when fn returns an error or panics, then
+// the error of fn is retried, so fn must return the errors of tx as they
+// are to have them classified, and have no side effects outside of tx.
+func InTx(ctx context.Context, db *sql.DB, r retry.Retrier, fn func(tx *sql.Tx) error) error {
+	if r == nil {
+		r = retry.New(retry.WithIsRetryableFunc(IsTxConflict))
+	}
+	return retry.AsContextRetrier(r).DoContext(ctx, func(ctx context.Context, _ int) error {
+		return runTx(ctx, db, fn)
+	})
+}
+
+// runTx runs fn in a transaction of db.
+func runTx(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) (err error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,78 @@
+package retrysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsTxConflict(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"serialization failure", stateError("40001"), true},
+		{"deadlock", stateError("40P01"), true},
+		{"connection failure", stateError("08006"), false},
+		{"mysql deadlock", &mysqlError{Number: 1213}, true},
+		{"mysql server gone", &mysqlError{Number: 2006}, false},
+		{"message", errors.New("Deadlock found when trying to get lock"), true},
+		{"other", errCustom, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsTxConflict(tt.err))
+		})
+	}
+}
+
+func TestInTx(t *testing.T) {
+	db, c := openFake(t, failFirst("COMMIT", stateError("40001")))
+
+	runs := 0
+	err := InTx(context.Background(), db, testRetrier(), func(tx *sql.Tx) error {
+		runs++
+		_, err := tx.Exec("UPDATE t SET n = n + 1")
+		return err
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, runs)
+	assert.Equal(t, []string{
+		"BEGIN", "UPDATE t SET n = n + 1", "COMMIT",
+		"BEGIN", "UPDATE t SET n = n + 1", "COMMIT",
+	}, c.statements(), "the whole transaction runs again")
+}
+
+func TestInTx_Rollback(t *testing.T) {
+	db, c := openFake(t, failFirst("UPDATE", stateError("40P01")))
+
+	err := InTx(context.Background(), db, nil, func(tx *sql.Tx) error {
+		_, err := tx.Exec("UPDATE")
+		if err != nil {
+			return err
+		}
+		return errCustom
+	})
+
+	assert.ErrorIs(t, err, errCustom)
+	assert.Equal(t, []string{
+		"BEGIN", "UPDATE", "ROLLBACK",
+		"BEGIN", "UPDATE", "ROLLBACK",
+	}, c.statements(), "only conflicts are retried")
+}
+
+func TestInTx_Panic(t *testing.T) {
+	db, c := openFake(t, nil)
+
+	assert.PanicsWithValue(t, "boom", func() {
+		_ = InTx(context.Background(), db, nil, func(*sql.Tx) error { panic("boom") })
+	})
+	assert.Equal(t, []string{"BEGIN", "ROLLBACK"}, c.statements())
+}
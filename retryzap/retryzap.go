@@ -0,0 +1,43 @@
+// Package retryzap logs retries with go.uber.org/zap, for services
+// standardized on zap rather than log/slog.
+package retryzap
+
+import (
+	"context"
+	"time"
+
+	"github.com/er-davo/retry"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// WithLogger logs every retry to logger at level with the same message and
+// fields as retry.WithSlog: the attempt number, the error and the delay
+// before the next attempt, plus the retrier name if set with
+// retry.WithName and the call's correlation ID if set with
+// retry.WithCorrelationID. Like retry.WithSlog, it only logs the calls
+// sampled by retry.WithTelemetrySampling.
+func WithLogger(logger *zap.Logger, level zapcore.Level) retry.RetryOption {
+	return retry.WithOnRetry(func(ctx context.Context, attempt int, err error, delay time.Duration) {
+		if !retry.TelemetrySampled(ctx) {
+			return
+		}
+		ce := logger.Check(level, "retrying after failed attempt")
+		if ce == nil {
+			return
+		}
+
+		fields := []zap.Field{
+			zap.Int("attempt", attempt),
+			zap.Error(err),
+			zap.Duration("delay", delay),
+		}
+		if name, ok := retry.NameFromContext(ctx); ok {
+			fields = append(fields, zap.String("retrier", name))
+		}
+		if id, ok := retry.CorrelationID(ctx); ok {
+			fields = append(fields, zap.String("call_id", id))
+		}
+		ce.Write(fields...)
+	})
+}
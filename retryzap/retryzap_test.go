@@ -0,0 +1,66 @@
+package retryzap
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/er-davo/retry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestWithLogger(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+
+	r := retry.New(
+		retry.WithMaxAttempts(3),
+		retry.WithBackoff(retry.FixedBackoff{Interval: time.Millisecond}),
+		retry.WithName("payments"),
+		retry.WithCorrelationID(func() string { return "c1" }),
+		WithLogger(zap.New(core), zapcore.WarnLevel),
+	)
+	_ = r.Do(context.Background(), func(attempt int) error { return errors.New("boom") })
+
+	entries := logs.All()
+	require.Len(t, entries, 2)
+
+	e := entries[1]
+	assert.Equal(t, zapcore.WarnLevel, e.Level)
+	assert.Equal(t, "retrying after failed attempt", e.Message)
+	fields := e.ContextMap()
+	assert.Equal(t, int64(1), fields["attempt"])
+	assert.Equal(t, "boom", fields["error"])
+	assert.Equal(t, time.Millisecond, fields["delay"])
+	assert.Equal(t, "c1", fields["call_id"])
+	assert.Equal(t, "payments", fields["retrier"])
+}
+
+func TestWithLogger_Sampling(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+
+	r := retry.New(
+		retry.WithMaxAttempts(2),
+		retry.WithBackoff(retry.FixedBackoff{Interval: time.Millisecond}),
+		retry.WithTelemetrySampling(0),
+		WithLogger(zap.New(core), zapcore.InfoLevel),
+	)
+	_ = r.Do(context.Background(), func(attempt int) error { return errors.New("boom") })
+	assert.Zero(t, logs.Len(), "calls sampled out are not logged")
+}
+
+func TestWithLogger_Disabled(t *testing.T) {
+	core, logs := observer.New(zapcore.ErrorLevel)
+
+	r := retry.New(
+		retry.WithMaxAttempts(2),
+		retry.WithBackoff(retry.FixedBackoff{Interval: time.Millisecond}),
+		WithLogger(zap.New(core), zapcore.InfoLevel),
+	)
+	_ = r.Do(context.Background(), func(attempt int) error { return errors.New("boom") })
+	assert.Zero(t, logs.Len())
+}
@@ -0,0 +1,293 @@
+package retry
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Rule actions.
+const (
+	ActionRetry = "retry"
+	ActionStop  = "stop"
+)
+
+// Rules is a declarative classifier configuration, typically decoded
+// from JSON or YAML so retryability can be adjusted without recompiling.
+//
+// Rules are evaluated in order and the first matching rule decides.
+// If none matches, Default applies (ActionRetry when empty).
+type Rules struct {
+	Default string `json:"default,omitempty"`
+	Rules   []Rule `json:"rules"`
+}
+
+// Rule matches an error and decides whether it is retried.
+//
+// All non-empty matchers must match for the rule to apply:
+//   - Is names a sentinel error, resolved through the sentinels passed to
+//     NewRuleClassifier and matched with errors.Is
+//   - Type is a type name as printed by %T (e.g. "*net.OpError"),
+//     matched against every error in the chain
+//   - Message is a regular expression matched against the error message
+//   - HTTPStatus lists status codes matched via StatusCoder
+//   - GRPCCode lists gRPC code names (e.g. "Unavailable") matched via
+//     the GRPCStatus method of gRPC status errors
+type Rule struct {
+	Action     string   `json:"action"`
+	Is         string   `json:"is,omitempty"`
+	Type       string   `json:"type,omitempty"`
+	Message    string   `json:"message,omitempty"`
+	HTTPStatus []int    `json:"http_status,omitempty"`
+	GRPCCode   []string `json:"grpc_code,omitempty"`
+}
+
+// NewRuleClassifier builds an IsRetryableFunc from rules. The sentinels
+// map resolves the names used by Rule.Is.
+//
+// It returns an error if an action is unknown, a sentinel name cannot be
+// resolved, a message pattern does not compile, or a rule has no matcher.
+func NewRuleClassifier(rules Rules, sentinels map[string]error) (IsRetryableFunc, error) {
+	def, err := ruleAction(rules.Default)
+	if err != nil {
+		return nil, fmt.Errorf("default: %w", err)
+	}
+
+	compiled := make([]compiledRule, len(rules.Rules))
+	for i, rule := range rules.Rules {
+		c, err := compileRule(rule, sentinels)
+		if err != nil {
+			return nil, fmt.Errorf("rule %d: %w", i, err)
+		}
+		compiled[i] = c
+	}
+
+	return func(err error) bool {
+		if err == nil {
+			return false
+		}
+		for _, c := range compiled {
+			if c.matches(err) {
+				return c.retry
+			}
+		}
+		return def
+	}, nil
+}
+
+type compiledRule struct {
+	retry      bool
+	sentinel   error
+	typeName   string
+	message    *regexp.Regexp
+	httpStatus []int
+	grpcCodes  []string
+}
+
+func compileRule(rule Rule, sentinels map[string]error) (compiledRule, error) {
+	retry, err := ruleAction(rule.Action)
+	if err != nil {
+		return compiledRule{}, err
+	}
+
+	c := compiledRule{
+		retry:      retry,
+		typeName:   rule.Type,
+		httpStatus: rule.HTTPStatus,
+		grpcCodes:  rule.GRPCCode,
+	}
+
+	if rule.Is != "" {
+		s, ok := sentinels[rule.Is]
+		if !ok {
+			return compiledRule{}, fmt.Errorf("unknown sentinel %q", rule.Is)
+		}
+		c.sentinel = s
+	}
+
+	if rule.Message != "" {
+		re, err := regexp.Compile(rule.Message)
+		if err != nil {
+			return compiledRule{}, fmt.Errorf("message: %w", err)
+		}
+		c.message = re
+	}
+
+	if c.sentinel == nil && c.typeName == "" && c.message == nil &&
+		len(c.httpStatus) == 0 && len(c.grpcCodes) == 0 {
+		return compiledRule{}, errors.New("no matcher")
+	}
+	return c, nil
+}
+
+func ruleAction(action string) (bool, error) {
+	switch action {
+	case ActionRetry, "":
+		return true, nil
+	case ActionStop:
+		return false, nil
+	}
+	return false, fmt.Errorf("unknown action %q", action)
+}
+
+func (c compiledRule) matches(err error) bool {
+	if c.sentinel != nil && !errors.Is(err, c.sentinel) {
+		return false
+	}
+	if c.typeName != "" && !hasTypeName(err, c.typeName) {
+		return false
+	}
+	if c.message != nil && !c.message.MatchString(err.Error()) {
+		return false
+	}
+	if len(c.httpStatus) > 0 {
+		code, ok := statusCode(err)
+		if !ok || !slices.Contains(c.httpStatus, code) {
+			return false
+		}
+	}
+	if len(c.grpcCodes) > 0 {
+		code, ok := grpcCodeName(err)
+		if !ok || !slices.Contains(c.grpcCodes, code) {
+			return false
+		}
+	}
+	return true
+}
+
+// hasTypeName reports whether any error in err's tree has the type name.
+func hasTypeName(err error, name string) bool {
+	return walkErrors(err, func(e error) bool {
+		return reflect.TypeOf(e).String() == name
+	})
+}
+
+// grpcCodeName returns the code name of the first gRPC status error in
+// err's chain without depending on the gRPC module.
+func grpcCodeName(err error) (string, bool) {
+	var found string
+	walkErrors(err, func(e error) bool {
+		m := reflect.ValueOf(e).MethodByName("GRPCStatus")
+		if !m.IsValid() || m.Type().NumIn() != 0 || m.Type().NumOut() != 1 {
+			return false
+		}
+
+		st := m.Call(nil)[0]
+		if st.Kind() == reflect.Pointer && st.IsNil() {
+			return false
+		}
+		code := st.MethodByName("Code")
+		if !code.IsValid() || code.Type().NumIn() != 0 || code.Type().NumOut() != 1 {
+			return false
+		}
+
+		s, ok := code.Call(nil)[0].Interface().(fmt.Stringer)
+		if ok {
+			found = s.String()
+		}
+		return ok
+	})
+	return found, found != ""
+}
+
+// walkErrors calls fn for every error in err's tree in pre-order until fn
+// returns true.
+func walkErrors(err error, fn func(error) bool) bool {
+	if err == nil {
+		return false
+	}
+	if fn(err) {
+		return true
+	}
+
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		return walkErrors(u.Unwrap(), fn)
+	case interface{ Unwrap() []error }:
+		for _, e := range u.Unwrap() {
+			if walkErrors(e, fn) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// WithNonRetryableErrorTypes retries every error except those whose chain
+// contains an error of one of the named types, mirroring Temporal's
+// NonRetryableErrorTypes retry policy field.
+//
+// A name matches either the type as printed by %T (e.g. "*pq.Error") or
+// its bare name without package and pointer (e.g. "MyFatalError").
+// A name may carry a ":code" suffix (e.g. "*pq.Error:23505") to match only
+// errors of that type whose code equals code; the code is read from an
+// SQLState, ErrorCode or Code method, or from a Code field.
+func WithNonRetryableErrorTypes(types ...string) RetryOption {
+	matchers := make([]typeNameMatcher, len(types))
+	for i, t := range types {
+		name, code, _ := strings.Cut(t, ":")
+		matchers[i] = typeNameMatcher{name: name, code: code}
+	}
+
+	return WithIsRetryableFunc(func(err error) bool {
+		if err == nil {
+			return false
+		}
+		for _, m := range matchers {
+			if walkErrors(err, m.matches) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+type typeNameMatcher struct {
+	name string
+	code string
+}
+
+func (m typeNameMatcher) matches(err error) bool {
+	t := reflect.TypeOf(err)
+	bare := t
+	for bare.Kind() == reflect.Pointer {
+		bare = bare.Elem()
+	}
+	if t.String() != m.name && bare.Name() != m.name {
+		return false
+	}
+	if m.code == "" {
+		return true
+	}
+
+	code, ok := errorCodeOf(err)
+	return ok && code == m.code
+}
+
+// errorCodeOf reads a driver- or library-specific code from err.
+func errorCodeOf(err error) (string, bool) {
+	v := reflect.ValueOf(err)
+	for _, name := range []string{"SQLState", "ErrorCode", "Code"} {
+		m := v.MethodByName(name)
+		if m.IsValid() && m.Type().NumIn() == 0 && m.Type().NumOut() == 1 {
+			return fmt.Sprint(m.Call(nil)[0].Interface()), true
+		}
+	}
+
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return "", false
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return "", false
+	}
+	if f := v.FieldByName("Code"); f.IsValid() && f.CanInterface() {
+		return fmt.Sprint(f.Interface()), true
+	}
+	return "", false
+}
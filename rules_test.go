@@ -0,0 +1,133 @@
+package retry
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeCode int
+
+func (c fakeCode) String() string {
+	if c == 14 {
+		return "Unavailable"
+	}
+	return "Unknown"
+}
+
+type fakeStatus struct{ code fakeCode }
+
+func (s *fakeStatus) Code() fakeCode { return s.code }
+
+// fakeGRPCError mirrors the shape of gRPC status errors.
+type fakeGRPCError struct{ code fakeCode }
+
+func (e *fakeGRPCError) Error() string           { return "rpc error" }
+func (e *fakeGRPCError) GRPCStatus() *fakeStatus { return &fakeStatus{code: e.code} }
+
+const rulesConfig = `{
+	"default": "stop",
+	"rules": [
+		{"action": "stop", "is": "unexpected_eof", "message": "^fatal"},
+		{"action": "retry", "is": "unexpected_eof"},
+		{"action": "retry", "type": "*net.OpError"},
+		{"action": "retry", "message": "(?i)try again"},
+		{"action": "retry", "http_status": [429, 503]},
+		{"action": "retry", "grpc_code": ["Unavailable"]}
+	]
+}`
+
+func TestNewRuleClassifier(t *testing.T) {
+	var rules Rules
+	require.NoError(t, json.Unmarshal([]byte(rulesConfig), &rules))
+
+	classify, err := NewRuleClassifier(rules, map[string]error{"unexpected_eof": io.ErrUnexpectedEOF})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"sentinel", fmt.Errorf("read: %w", io.ErrUnexpectedEOF), true},
+		{"first rule wins", fmt.Errorf("fatal: %w", io.ErrUnexpectedEOF), false},
+		{"type name", fmt.Errorf("dial: %w", &net.OpError{Op: "dial", Err: errCustom}), true},
+		{"message", fmt.Errorf("Please TRY AGAIN later"), true},
+		{"http status", statusError(503), true},
+		{"http status mismatch", statusError(404), false},
+		{"grpc code", &fakeGRPCError{code: 14}, true},
+		{"grpc code mismatch", &fakeGRPCError{code: 2}, false},
+		{"default", errCustom, false},
+		{"nil", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, classify(tt.err))
+		})
+	}
+}
+
+func TestNewRuleClassifier_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   Rules
+		wantErr string
+	}{
+		{"unknown default", Rules{Default: "maybe"}, `default: unknown action "maybe"`},
+		{"unknown action", Rules{Rules: []Rule{{Action: "later", Message: "x"}}}, `rule 0: unknown action "later"`},
+		{"unknown sentinel", Rules{Rules: []Rule{{Is: "missing"}}}, `rule 0: unknown sentinel "missing"`},
+		{"bad pattern", Rules{Rules: []Rule{{Message: "("}}}, "rule 0: message: error parsing regexp: missing closing ): `(`"},
+		{"no matcher", Rules{Rules: []Rule{{Action: ActionStop}}}, "rule 0: no matcher"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewRuleClassifier(tt.rules, nil)
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+type pqLikeError struct{ Code string }
+
+func (e *pqLikeError) Error() string { return "pq: " + e.Code }
+
+type MyFatalError struct{}
+
+func (MyFatalError) Error() string { return "fatal" }
+
+func TestWithNonRetryableErrorTypes(t *testing.T) {
+	r := New(WithNonRetryableErrorTypes("*retry.pqLikeError:23505", "MyFatalError", "stateError:40P01")).(*retrier)
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"type with code", fmt.Errorf("insert: %w", &pqLikeError{Code: "23505"}), false},
+		{"type with other code", &pqLikeError{Code: "40001"}, true},
+		{"bare name", fmt.Errorf("op: %w", MyFatalError{}), false},
+		{"bare name pointer", &MyFatalError{}, false},
+		{"code from method", stateError("40P01"), false},
+		{"code from method mismatch", stateError("40001"), true},
+		{"other type", errCustom, true},
+		{"nil", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, r.isRetryable(tt.err))
+		})
+	}
+}
+
+type stateError string
+
+func (e stateError) Error() string    { return "sqlstate " + string(e) }
+func (e stateError) SQLState() string { return string(e) }
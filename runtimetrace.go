@@ -0,0 +1,49 @@
+package retry
+
+import (
+	"context"
+	"runtime/trace"
+	"strconv"
+)
+
+// Names of the runtime/trace tasks and regions created by WithRuntimeTrace.
+const (
+	TraceTask          = "retry.Do"
+	TraceAttemptRegion = "retry.attempt"
+	TraceBackoffRegion = "retry.backoff"
+)
+
+// WithRuntimeTrace wraps every Do call in a runtime/trace task, and every
+// attempt and backoff sleep in a region of it, so `go tool trace` shows the
+// structure of retried operations. The task is named TraceTask, or
+// TraceTask followed by the name set with WithName. Its overhead is
+// negligible while tracing is not running.
+func WithRuntimeTrace() RetryOption {
+	return func(r *retrier) {
+		r.runtimeTrace = true
+	}
+}
+
+// traceTask starts the task of a call if runtime tracing is configured.
+func (r retrier) traceTask(ctx context.Context) (context.Context, func()) {
+	if !r.runtimeTrace {
+		return ctx, func() {}
+	}
+
+	name := TraceTask
+	if r.name != "" {
+		name += " " + r.name
+	}
+	ctx, task := trace.NewTask(ctx, name)
+	return ctx, task.End
+}
+
+// traceRegion starts a region if runtime tracing is configured and running.
+func (r retrier) traceRegion(ctx context.Context, region string, attempt int) func() {
+	if !r.runtimeTrace || !trace.IsEnabled() {
+		return func() {}
+	}
+
+	trace.Log(ctx, "attempt", strconv.Itoa(attempt))
+	return trace.StartRegion(ctx, region).End
+}
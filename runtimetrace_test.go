@@ -0,0 +1,35 @@
+package retry
+
+import (
+	"bytes"
+	"context"
+	"runtime/trace"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithRuntimeTrace(t *testing.T) {
+	if trace.IsEnabled() {
+		t.Skip("tracing already running")
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, trace.Start(&buf))
+
+	r := New(
+		WithName("inventory"),
+		WithMaxAttempts(2),
+		WithBackoff(FixedBackoff{Interval: time.Millisecond}),
+		WithRuntimeTrace(),
+	)
+	_ = r.Do(context.Background(), func(attempt int) error { return errAlwaysFail })
+	trace.Stop()
+
+	out := buf.String()
+	assert.Contains(t, out, TraceTask+" inventory")
+	assert.Contains(t, out, TraceAttemptRegion)
+	assert.Contains(t, out, TraceBackoffRegion)
+}
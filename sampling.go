@@ -0,0 +1,66 @@
+package retry
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+)
+
+// WithTelemetrySampling emits logs, events and attempt spans for only a
+// fraction rate of Do calls, keeping overhead and telemetry volume bounded
+// on hot paths. The decision is made once per call, so a sampled call is
+// observed in full. Metrics, stats and audit records are not sampled.
+// Middleware can check the decision with TelemetrySampled.
+func WithTelemetrySampling(rate float64) RetryOption {
+	return func(r *retrier) {
+		r.sampleRate = min(max(rate, 0), 1)
+		r.sampling = true
+	}
+}
+
+type sampledKey struct{}
+
+// TelemetrySampled reports whether telemetry should be emitted for the Do
+// call ctx belongs to. It is true unless the call was sampled out by
+// WithTelemetrySampling.
+func TelemetrySampled(ctx context.Context) bool {
+	sampled, ok := ctx.Value(sampledKey{}).(bool)
+	return !ok || sampled
+}
+
+// sample records the sampling decision for a call on ctx.
+func (r retrier) sample(ctx context.Context) context.Context {
+	if !r.sampling {
+		return ctx
+	}
+	return context.WithValue(ctx, sampledKey{}, rand.Float64() < r.sampleRate)
+}
+
+// sampledHook forwards to h for sampled calls only.
+type sampledHook struct {
+	h hook
+}
+
+func (s sampledHook) attemptStarted(ctx context.Context, attempt int) {
+	if TelemetrySampled(ctx) {
+		s.h.attemptStarted(ctx, attempt)
+	}
+}
+
+func (s sampledHook) attemptFinished(ctx context.Context, attempt int, err error, d time.Duration) {
+	if TelemetrySampled(ctx) {
+		s.h.attemptFinished(ctx, attempt, err, d)
+	}
+}
+
+func (s sampledHook) retrying(ctx context.Context, attempt int, err error, delay time.Duration) {
+	if TelemetrySampled(ctx) {
+		s.h.retrying(ctx, attempt, err, delay)
+	}
+}
+
+func (s sampledHook) finished(ctx context.Context, attempts int, err error) {
+	if TelemetrySampled(ctx) {
+		s.h.finished(ctx, attempts, err)
+	}
+}
@@ -0,0 +1,70 @@
+package retry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithTelemetrySampling(t *testing.T) {
+	tests := []struct {
+		name   string
+		rate   float64
+		events int
+	}{
+		{name: "none", rate: 0, events: 0},
+		{name: "all", rate: 1, events: 100 * 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := make(chan Event, 1000)
+			m := &recordingMetrics{}
+			r := New(
+				WithMaxAttempts(1),
+				WithEventSink(events),
+				WithMetrics(m),
+				WithTelemetrySampling(tt.rate),
+			)
+			for range 100 {
+				_ = r.DoContext(context.Background(), func(ctx context.Context, attempt int) error {
+					assert.Equal(t, tt.rate == 1, TelemetrySampled(ctx))
+					return errAlwaysFail
+				})
+			}
+
+			assert.Len(t, events, tt.events)
+			assert.Equal(t, 100, m.attempts, "metrics are not sampled")
+		})
+	}
+}
+
+func TestWithTelemetrySampling_PerCall(t *testing.T) {
+	events := make(chan Event, 10000)
+	r := New(
+		WithMaxAttempts(2),
+		WithBackoff(FixedBackoff{Interval: time.Nanosecond}),
+		WithEventSink(events),
+		WithTelemetrySampling(0.5),
+	)
+	for range 1000 {
+		_ = r.Do(context.Background(), func(attempt int) error { return errAlwaysFail })
+	}
+	close(events)
+
+	var gaveUp, total int
+	for e := range events {
+		total++
+		if e.Kind == GaveUp {
+			gaveUp++
+		}
+	}
+	assert.InDelta(t, 500, gaveUp, 100)
+	assert.Equal(t, gaveUp*6, total, "sampled calls emit every event")
+}
+
+func TestTelemetrySampled_Default(t *testing.T) {
+	assert.True(t, TelemetrySampled(context.Background()))
+}
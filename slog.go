@@ -0,0 +1,62 @@
+package retry
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// WithSlog logs every retry to logger at level with the attempt number,
+// the error and the delay before the next attempt as structured attributes.
+func WithSlog(logger *slog.Logger, level slog.Level) RetryOption {
+	return func(r *retrier) {
+		r.logger = logger
+		r.logLevel = level
+	}
+}
+
+// WithLoggerFromContext logs every retry to the logger returned by fn for
+// the Do call's context, so retry logs carry request-scoped attributes such
+// as trace IDs. If fn returns nil, the logger set by WithSlog is used.
+// The level is the one set by WithSlog, or slog.LevelInfo.
+func WithLoggerFromContext(fn func(context.Context) *slog.Logger) RetryOption {
+	return func(r *retrier) {
+		r.loggerFromContext = fn
+	}
+}
+
+// slogHook logs retries as configured on r.
+type slogHook struct {
+	nopHook
+	r *retrier
+}
+
+func (h slogHook) retrying(ctx context.Context, attempt int, err error, delay time.Duration) {
+	h.r.logRetry(ctx, attempt, err, delay)
+}
+
+// logRetry logs a retry if logging is configured.
+func (r *retrier) logRetry(ctx context.Context, attempt int, err error, delay time.Duration) {
+	logger := r.logger
+	if r.loggerFromContext != nil {
+		if l := r.loggerFromContext(ctx); l != nil {
+			logger = l
+		}
+	}
+	if logger == nil || !logger.Enabled(ctx, r.logLevel) {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.Int("attempt", attempt),
+		slog.Any("error", err),
+		slog.Duration("delay", delay),
+	}
+	if r.name != "" {
+		attrs = append(attrs, slog.String("retrier", r.name))
+	}
+	if id, ok := CorrelationID(ctx); ok {
+		attrs = append(attrs, slog.String("call_id", id))
+	}
+	logger.LogAttrs(ctx, r.logLevel, "retrying after failed attempt", attrs...)
+}
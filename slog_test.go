@@ -0,0 +1,77 @@
+package retry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithSlog(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	r := New(
+		WithMaxAttempts(3),
+		WithBackoff(FixedBackoff{Interval: time.Millisecond}),
+		WithSlog(logger, slog.LevelWarn),
+	)
+	_ = r.Do(context.Background(), func(attempt int) error { return errAlwaysFail })
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+
+	var rec map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &rec))
+	assert.Equal(t, "WARN", rec["level"])
+	assert.Equal(t, "retrying after failed attempt", rec["msg"])
+	assert.Equal(t, float64(1), rec["attempt"])
+	assert.Equal(t, "always fail", rec["error"])
+	assert.Equal(t, float64(time.Millisecond), rec["delay"])
+}
+
+func TestWithSlog_Disabled(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	r := New(
+		WithMaxAttempts(2),
+		WithBackoff(FixedBackoff{Interval: time.Millisecond}),
+		WithSlog(logger, slog.LevelInfo),
+	)
+	_ = r.Do(context.Background(), func(attempt int) error { return errAlwaysFail })
+	assert.Empty(t, buf.String())
+}
+
+type loggerKey struct{}
+
+func TestWithLoggerFromContext(t *testing.T) {
+	var global, scoped bytes.Buffer
+	globalLogger := slog.New(slog.NewTextHandler(&global, nil))
+	scopedLogger := slog.New(slog.NewTextHandler(&scoped, nil)).With("trace_id", "abc123")
+
+	r := New(
+		WithMaxAttempts(2),
+		WithBackoff(FixedBackoff{Interval: time.Millisecond}),
+		WithSlog(globalLogger, slog.LevelWarn),
+		WithLoggerFromContext(func(ctx context.Context) *slog.Logger {
+			l, _ := ctx.Value(loggerKey{}).(*slog.Logger)
+			return l
+		}),
+	)
+
+	ctx := context.WithValue(context.Background(), loggerKey{}, scopedLogger)
+	_ = r.Do(ctx, func(attempt int) error { return errAlwaysFail })
+	assert.Contains(t, scoped.String(), "trace_id=abc123")
+	assert.Contains(t, scoped.String(), "level=WARN")
+	assert.Empty(t, global.String())
+
+	_ = r.Do(context.Background(), func(attempt int) error { return errAlwaysFail })
+	assert.Contains(t, global.String(), "attempt=0", "falls back to the WithSlog logger")
+}
@@ -0,0 +1,145 @@
+package retry
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// Stats is a snapshot of a retrier's cumulative counters.
+type Stats struct {
+	// Calls is the number of finished Do calls.
+	Calls uint64 `json:"calls"`
+	// Attempts is the number of attempts made.
+	Attempts uint64 `json:"attempts"`
+	// Retries is the number of retries scheduled after failed attempts.
+	Retries uint64 `json:"retries"`
+	// Successes is the number of calls that succeeded.
+	Successes uint64 `json:"successes"`
+	// FirstTrySuccesses and RetriedSuccesses split successful calls into
+	// those that succeeded on their first attempt and those that needed
+	// retries.
+	FirstTrySuccesses uint64 `json:"first_try_successes"`
+	RetriedSuccesses  uint64 `json:"retried_successes"`
+	// Exhaustions is the number of calls that ran out of attempts.
+	Exhaustions uint64 `json:"exhaustions"`
+	// AvgAttemptsPerCall is the mean number of attempts per finished call.
+	AvgAttemptsPerCall float64 `json:"avg_attempts_per_call"`
+	// InFlight is the number of Do calls in progress.
+	InFlight int64 `json:"in_flight"`
+	// Sleeping is the number of Do calls in progress waiting between
+	// attempts; the others are executing an attempt.
+	Sleeping int64 `json:"sleeping"`
+	// AttemptsToSuccess is a histogram of the attempts successful calls
+	// needed: element i counts calls that succeeded on attempt i+1, and the
+	// last element counts calls needing StatsHistogramSize attempts or more.
+	AttemptsToSuccess [StatsHistogramSize]uint64 `json:"attempts_to_success"`
+}
+
+// StatsHistogramSize is the number of buckets of Stats.AttemptsToSuccess.
+const StatsHistogramSize = 16
+
+// StatsReporter is implemented by retriers created with WithStats.
+type StatsReporter interface {
+	Stats() Stats
+}
+
+// WithStats enables cumulative counters maintained with atomics, exposed
+// through the retrier's Stats method for lightweight health endpoints:
+//
+//	if sr, ok := r.(retry.StatsReporter); ok {
+//	    stats := sr.Stats()
+//	}
+func WithStats() RetryOption {
+	return func(r *retrier) {
+		if r.stats == nil {
+			r.stats = &statsHook{}
+			r.extraHooks = append(r.extraHooks, r.stats)
+		}
+	}
+}
+
+// Stats returns a snapshot of the retrier's counters. Without WithStats
+// all counters are zero.
+func (r retrier) Stats() Stats {
+	if r.stats == nil {
+		return Stats{}
+	}
+	s := r.stats.snapshot()
+	s.InFlight = r.live.inFlight.Load()
+	s.Sleeping = r.live.sleeping.Load()
+	return s
+}
+
+// liveState tracks the calls of a retrier that are in progress.
+type liveState struct {
+	inFlight atomic.Int64
+	sleeping atomic.Int64
+}
+
+// trackInFlight adds delta to the number of calls in progress.
+func (r retrier) trackInFlight(delta int) {
+	r.live.inFlight.Add(int64(delta))
+	r.metrics.AddInFlight(delta)
+}
+
+// trackSleeping adds delta to the number of calls waiting between attempts.
+func (r retrier) trackSleeping(delta int) {
+	r.live.sleeping.Add(int64(delta))
+	r.metrics.AddSleeping(delta)
+}
+
+// statsHook maintains the counters behind Stats.
+type statsHook struct {
+	calls       atomic.Uint64
+	attempts    atomic.Uint64
+	retries     atomic.Uint64
+	successes   atomic.Uint64
+	exhaustions atomic.Uint64
+
+	attemptsToSuccess [StatsHistogramSize]atomic.Uint64
+
+	// callAttempts sums the attempts of finished calls, so the average is
+	// not skewed by calls still in flight.
+	callAttempts atomic.Uint64
+}
+
+func (h *statsHook) attemptStarted(context.Context, int) { h.attempts.Add(1) }
+
+func (h *statsHook) attemptFinished(context.Context, int, error, time.Duration) {}
+
+func (h *statsHook) retrying(context.Context, int, error, time.Duration) { h.retries.Add(1) }
+
+func (h *statsHook) finished(_ context.Context, attempts int, err error) {
+	h.callAttempts.Add(uint64(attempts))
+	switch {
+	case err == nil:
+		h.successes.Add(1)
+		h.attemptsToSuccess[min(attempts, StatsHistogramSize)-1].Add(1)
+	case isExhausted(err):
+		h.exhaustions.Add(1)
+	}
+	h.calls.Add(1)
+}
+
+func (h *statsHook) snapshot() Stats {
+	s := Stats{
+		Calls:       h.calls.Load(),
+		Attempts:    h.attempts.Load(),
+		Retries:     h.retries.Load(),
+		Successes:   h.successes.Load(),
+		Exhaustions: h.exhaustions.Load(),
+	}
+	for i := range h.attemptsToSuccess {
+		s.AttemptsToSuccess[i] = h.attemptsToSuccess[i].Load()
+		if i == 0 {
+			s.FirstTrySuccesses = s.AttemptsToSuccess[i]
+		} else {
+			s.RetriedSuccesses += s.AttemptsToSuccess[i]
+		}
+	}
+	if s.Calls > 0 {
+		s.AvgAttemptsPerCall = float64(h.callAttempts.Load()) / float64(s.Calls)
+	}
+	return s
+}
@@ -0,0 +1,110 @@
+package retry
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithStats(t *testing.T) {
+	r := New(
+		WithMaxAttempts(3),
+		WithBackoff(FixedBackoff{Interval: time.Millisecond}),
+		WithStats(),
+	)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = r.Do(context.Background(), func(attempt int) error { return nil })
+		}()
+	}
+	wg.Wait()
+	_ = r.Do(context.Background(), func(attempt int) error { return errAlwaysFail })
+	_ = r.Do(context.Background(), func(attempt int) error {
+		if attempt == 0 {
+			return errAlwaysFail
+		}
+		return nil
+	})
+
+	sr, ok := r.(StatsReporter)
+	require.True(t, ok)
+	stats := sr.Stats()
+	assert.Equal(t, uint64(6), stats.Calls)
+	assert.Equal(t, uint64(9), stats.Attempts)
+	assert.Equal(t, uint64(3), stats.Retries)
+	assert.Equal(t, uint64(5), stats.Successes)
+	assert.Equal(t, uint64(1), stats.Exhaustions)
+	assert.Equal(t, 1.5, stats.AvgAttemptsPerCall)
+	assert.Equal(t, uint64(4), stats.FirstTrySuccesses)
+	assert.Equal(t, uint64(1), stats.RetriedSuccesses)
+	assert.Equal(t, uint64(4), stats.AttemptsToSuccess[0])
+	assert.Equal(t, uint64(1), stats.AttemptsToSuccess[1])
+}
+
+func TestStatsDisabled(t *testing.T) {
+	r := New()
+	_ = r.Do(context.Background(), func(attempt int) error { return nil })
+	assert.Equal(t, Stats{}, r.(StatsReporter).Stats())
+}
+
+func TestStatsAttemptsToSuccessOverflow(t *testing.T) {
+	r := New(
+		WithMaxAttempts(0),
+		WithBackoff(FixedBackoff{}),
+		WithStats(),
+	)
+	_ = r.Do(context.Background(), func(attempt int) error {
+		if attempt < StatsHistogramSize+3 {
+			return errAlwaysFail
+		}
+		return nil
+	})
+
+	stats := r.(StatsReporter).Stats()
+	assert.Equal(t, uint64(1), stats.AttemptsToSuccess[StatsHistogramSize-1])
+}
+
+func TestStatsInFlight(t *testing.T) {
+	r := New(
+		WithMaxAttempts(2),
+		WithBackoff(FixedBackoff{Interval: time.Hour}),
+		WithStats(),
+	)
+	sr := r.(StatsReporter)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	running := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = r.Do(ctx, func(attempt int) error {
+			close(running)
+			<-release
+			return errAlwaysFail
+		})
+	}()
+
+	<-running
+	stats := sr.Stats()
+	assert.Equal(t, int64(1), stats.InFlight)
+	assert.Zero(t, stats.Sleeping)
+
+	close(release)
+	require.Eventually(t, func() bool { return sr.Stats().Sleeping == 1 }, time.Second, time.Millisecond)
+	assert.Equal(t, int64(1), sr.Stats().InFlight)
+
+	cancel()
+	<-done
+	stats = sr.Stats()
+	assert.Zero(t, stats.InFlight)
+	assert.Zero(t, stats.Sleeping)
+}
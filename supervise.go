@@ -0,0 +1,68 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrExited is reported by Supervise when the supervised function returns
+// nil before running for the healthy uptime.
+var ErrExited = errors.New("supervised function exited")
+
+// SuperviseOption configures Supervise.
+type SuperviseOption func(*supervisor)
+
+// WithHealthyUptime sets the time after which a run of the supervised
+// function is healthy: when it ends, the function is restarted at once with
+// the backoff reset, instead of after the next backoff delay. The default
+// is one minute.
+func WithHealthyUptime(d time.Duration) SuperviseOption {
+	return func(s *supervisor) {
+		s.healthyUptime = d
+	}
+}
+
+type supervisor struct {
+	healthyUptime time.Duration
+}
+
+// Supervise runs f, a long-lived function such as a worker or a consumer
+// loop, until ctx is done, restarting it with the attempts and backoff of
+// r whenever it returns or panics, the classic supervision-tree pattern.
+// Every run is an attempt of r: a run ending with an error, a panic,
+// reported as a *PanicError, or nil, reported as ErrExited, is retried
+// like any failed attempt. A run lasting for the healthy uptime ends the
+// attempts, so the next failures start over from the first backoff delay.
+//
+// Supervise returns ctx.Err() once ctx is done, or the error of r if it
+// stops retrying, e.g. after its maximum number of attempts.
+func Supervise(ctx context.Context, r Retrier, f func(ctx context.Context) error, opts ...SuperviseOption) error {
+	s := supervisor{healthyUptime: time.Minute}
+	for _, opt := range opts {
+		opt(&s)
+	}
+
+	for {
+		err := doContext(ctx, r, func(ctx context.Context, _ int) error {
+			start := time.Now()
+			err := recovered(func() error { return f(ctx) })
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			if time.Since(start) >= s.healthyUptime {
+				return nil
+			}
+			if err == nil {
+				err = ErrExited
+			}
+			return err
+		})
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
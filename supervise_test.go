@@ -0,0 +1,58 @@
+package retry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSupervise(t *testing.T) {
+	var delays []time.Duration
+	r := New(
+		WithMaxAttempts(3),
+		WithBackoff(ExponentialBackoff{Base: time.Millisecond, Factor: 2}),
+		WithOnRetry(func(_ context.Context, _ int, _ error, d time.Duration) {
+			delays = append(delays, d)
+		}),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	runs := 0
+	err := Supervise(ctx, r, func(ctx context.Context) error {
+		runs++
+		switch runs {
+		case 1:
+			return errAlwaysFail
+		case 2:
+			panic("boom")
+		case 3:
+			time.Sleep(20 * time.Millisecond) // healthy
+			return errAlwaysFail
+		case 4:
+			return nil
+		}
+		cancel()
+		return nil
+	}, WithHealthyUptime(10*time.Millisecond))
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 5, runs)
+	assert.Equal(t, []time.Duration{time.Millisecond, 2 * time.Millisecond, time.Millisecond}, delays,
+		"the backoff is reset after a healthy run")
+}
+
+func TestSupervise_GivesUp(t *testing.T) {
+	r := New(WithMaxAttempts(2), WithBackoff(FixedBackoff{}))
+	err := Supervise(context.Background(), r, func(context.Context) error {
+		panic("boom")
+	})
+
+	var pe *PanicError
+	assert.ErrorAs(t, err, &pe)
+	assert.Equal(t, "boom", pe.Value)
+	assert.Contains(t, string(pe.Stack), "supervise_test.go")
+}
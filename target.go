@@ -0,0 +1,114 @@
+package retry
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// TargetSelector returns the index, in [0, n), of the target attempt is
+// directed at. call numbers the calls of the retrier from 0, and is the
+// same for every attempt of a call.
+type TargetSelector func(call uint64, attempt, n int) int
+
+// RoundRobin returns a TargetSelector cycling through the targets: every
+// call starts at the target after the one the previous call started at,
+// and each of its retries moves on to the next target, so calls are spread
+// evenly across the targets and a retry never goes to the target that just
+// failed, unless there is only one.
+func RoundRobin() TargetSelector {
+	return func(call uint64, attempt, n int) int {
+		return int((call + uint64(attempt)) % uint64(n))
+	}
+}
+
+type targetKey struct{}
+
+// WithTargets directs every attempt at one of targets, e.g. an endpoint or
+// a replica, chosen by selector, so a failed attempt can be retried against
+// a different backend. A nil selector means RoundRobin. Attempts read their
+// target with TargetFromContext, so they must be run with DoContext.
+// WithTargets does nothing if targets is empty. See also WithStickyTarget.
+func WithTargets[T any](targets []T, selector TargetSelector) RetryOption {
+	if selector == nil {
+		selector = RoundRobin()
+	}
+	targets = append([]T(nil), targets...)
+	var calls atomic.Uint64
+
+	return func(r *retrier) {
+		if len(targets) == 0 {
+			return
+		}
+		r.middleware = append(r.middleware, func(next ContextAttemptFunc) ContextAttemptFunc {
+			// Middleware is applied once per call.
+			call := calls.Add(1) - 1
+			return func(ctx context.Context, attempt int) error {
+				i, ok := r.sticky.get(attempt)
+				if !ok {
+					i = selector(call, attempt, len(targets))
+				}
+				err := next(context.WithValue(ctx, targetKey{}, targets[i]), attempt)
+				if err == nil {
+					r.sticky.set(i)
+				}
+				return err
+			}
+		})
+	}
+}
+
+// TargetFromContext returns the target of the attempt executing with ctx,
+// set with WithTargets.
+func TargetFromContext[T any](ctx context.Context) (T, bool) {
+	t, ok := ctx.Value(targetKey{}).(T)
+	return t, ok
+}
+
+// WithStickyTarget makes the first attempt of every call go to the target
+// set with WithTargets that served the last successful attempt, if it
+// succeeded less than decay ago, instead of the one chosen by the selector.
+// This avoids paying the discovery of a healthy target again on every
+// call, while the decay lets the calls spread again once it is stale.
+func WithStickyTarget(decay time.Duration) RetryOption {
+	return func(r *retrier) {
+		r.sticky = &stickyTarget{decay: decay, now: time.Now}
+	}
+}
+
+// stickyTarget remembers the target of the last successful attempt. A nil
+// *stickyTarget remembers nothing.
+type stickyTarget struct {
+	decay time.Duration
+	now   func() time.Time
+
+	mu    sync.Mutex
+	index int
+	at    time.Time
+}
+
+// get returns the sticky target index if attempt is the first one of its
+// call and the last success is fresher than the decay.
+func (s *stickyTarget) get(attempt int) (int, bool) {
+	if s == nil || attempt > 0 {
+		return 0, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.at.IsZero() || s.now().Sub(s.at) >= s.decay {
+		return 0, false
+	}
+	return s.index, true
+}
+
+// set records a success of the target at index.
+func (s *stickyTarget) set(index int) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.index = index
+	s.at = s.now()
+}
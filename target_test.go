@@ -0,0 +1,124 @@
+package retry
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithTargets(t *testing.T) {
+	tests := []struct {
+		name     string
+		selector TargetSelector
+		want     []string
+	}{
+		{name: "round robin", want: []string{"a", "b", "c", "a"}},
+		{
+			name:     "custom",
+			selector: func(_ uint64, attempt, n int) int { return n - 1 - attempt%n },
+			want:     []string{"c", "b", "a", "c"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New(
+				WithMaxAttempts(4),
+				WithBackoff(FixedBackoff{}),
+				WithTargets([]string{"a", "b", "c"}, tt.selector),
+			)
+
+			var got []string
+			err := r.DoContext(context.Background(), func(ctx context.Context, _ int) error {
+				target, ok := TargetFromContext[string](ctx)
+				require.True(t, ok)
+				got = append(got, target)
+				return errAlwaysFail
+			})
+			assert.Error(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestWithTargets_RoundRobinConcurrent(t *testing.T) {
+	targets := []string{"a", "b"}
+	r := New(
+		WithMaxAttempts(4),
+		WithBackoff(FixedBackoff{}),
+		WithTargets(targets, nil),
+	)
+
+	var (
+		mu     sync.Mutex
+		starts = make(map[string]int)
+		wg     sync.WaitGroup
+	)
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var prev string
+			_ = r.DoContext(context.Background(), func(ctx context.Context, attempt int) error {
+				target, _ := TargetFromContext[string](ctx)
+				if attempt == 0 {
+					mu.Lock()
+					starts[target]++
+					mu.Unlock()
+				} else {
+					assert.NotEqual(t, prev, target, "a retry goes to another target")
+				}
+				prev = target
+				return errAlwaysFail
+			})
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, map[string]int{"a": 25, "b": 25}, starts, "calls start on every target in turn")
+}
+
+func TestWithTargets_Empty(t *testing.T) {
+	r := New(WithTargets([]string(nil), nil))
+	err := r.DoContext(context.Background(), func(ctx context.Context, _ int) error {
+		_, ok := TargetFromContext[string](ctx)
+		assert.False(t, ok)
+		return nil
+	})
+	assert.NoError(t, err)
+}
+
+func TestWithStickyTarget(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(0, 0)}
+	r := New(
+		WithMaxAttempts(3),
+		WithBackoff(FixedBackoff{}),
+		WithTargets([]string{"a", "b", "c"}, nil),
+		WithStickyTarget(time.Minute),
+	)
+	r.(*retrier).sticky.now = clock.now
+
+	// call runs a call succeeding on target healthy and returns the
+	// targets of its attempts.
+	call := func(healthy string) []string {
+		var got []string
+		_ = r.DoContext(context.Background(), func(ctx context.Context, _ int) error {
+			target, _ := TargetFromContext[string](ctx)
+			got = append(got, target)
+			if target != healthy {
+				return errAlwaysFail
+			}
+			return nil
+		})
+		return got
+	}
+
+	assert.Equal(t, []string{"a", "b"}, call("b"))
+	assert.Equal(t, []string{"b"}, call("b"), "the last successful target is tried first")
+	clock.advance(time.Minute)
+	assert.Equal(t, []string{"c", "a", "b"}, call("b"), "stale stickiness decays")
+	assert.Equal(t, []string{"b"}, call("b"))
+}
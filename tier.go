@@ -0,0 +1,98 @@
+package retry
+
+import (
+	"errors"
+	"time"
+)
+
+// Tier classifies a failed attempt by how it should be retried.
+type Tier int
+
+const (
+	// RetryFast retries with the regular backoff delay.
+	RetryFast Tier = iota
+	// RetrySlow retries with the backoff delay scaled by the slow multiplier,
+	// e.g. for rate-limit errors that should be retried more gently.
+	RetrySlow
+	// Fatal stops retries immediately.
+	Fatal
+)
+
+// String returns the name of the tier.
+func (t Tier) String() string {
+	switch t {
+	case RetryFast:
+		return "RetryFast"
+	case RetrySlow:
+		return "RetrySlow"
+	case Fatal:
+		return "Fatal"
+	}
+	return "Tier(unknown)"
+}
+
+// TierFunc classifies an error into a Tier.
+type TierFunc func(error) Tier
+
+// defaultTierMultipliers returns the default delay multiplier per tier.
+func defaultTierMultipliers() map[Tier]float64 {
+	return map[Tier]float64{
+		RetryFast: 1,
+		RetrySlow: 4,
+	}
+}
+
+// WithTierFunc sets a function that classifies errors into tiers.
+// When set, it takes precedence over the IsRetryableFunc: Fatal stops
+// retries and the retry tiers scale the backoff delay by their multiplier.
+func WithTierFunc(tierFunc TierFunc) RetryOption {
+	return func(r *retrier) {
+		r.tierFunc = tierFunc
+	}
+}
+
+// WithTierMultiplier sets the factor applied to the backoff delay of
+// attempts classified into tier. By default RetryFast uses 1 and
+// RetrySlow uses 4. Delay hints carried by errors are not scaled.
+func WithTierMultiplier(tier Tier, multiplier float64) RetryOption {
+	return func(r *retrier) {
+		r.tierMultipliers[tier] = multiplier
+	}
+}
+
+// classify decides whether err is retryable and returns the factor to
+// apply to the backoff delay.
+func (r retrier) classify(err error) (bool, float64) {
+	if errors.Is(err, ErrCircuitOpen) || IsUnretryable(err) {
+		return false, 0
+	}
+	if r.fatalPanics {
+		if pe := (*PanicError)(nil); errors.As(err, &pe) {
+			return false, 0
+		}
+	}
+
+	if r.tierFunc != nil {
+		tier := r.tierFunc(err)
+		if tier == Fatal {
+			return false, 0
+		}
+		if m, ok := r.tierMultipliers[tier]; ok {
+			return true, m
+		}
+		return true, 1
+	}
+
+	if r.isRetryable != nil && !r.isRetryable(err) {
+		return false, 0
+	}
+	return true, 1
+}
+
+// scaleDelay multiplies d by m.
+func scaleDelay(d time.Duration, m float64) time.Duration {
+	if m == 1 {
+		return d
+	}
+	return time.Duration(float64(d) * m)
+}
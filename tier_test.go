@@ -0,0 +1,85 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errRateLimited = errors.New("rate limited")
+
+func testTierFunc(err error) Tier {
+	switch {
+	case errors.Is(err, errRateLimited):
+		return RetrySlow
+	case errors.Is(err, errCustom):
+		return Fatal
+	}
+	return RetryFast
+}
+
+func TestRetrier_Classify(t *testing.T) {
+	tests := []struct {
+		name           string
+		opts           []RetryOption
+		err            error
+		wantRetryable  bool
+		wantMultiplier float64
+	}{
+		{"default", nil, errAlwaysFail, true, 1},
+		{"retryable func", []RetryOption{WithUnretryableErrors(errCustom)}, errCustom, false, 0},
+		{"fast tier", []RetryOption{WithTierFunc(testTierFunc)}, errAlwaysFail, true, 1},
+		{"slow tier", []RetryOption{WithTierFunc(testTierFunc)}, errRateLimited, true, 4},
+		{"fatal tier", []RetryOption{WithTierFunc(testTierFunc)}, errCustom, false, 0},
+		{
+			"custom multiplier",
+			[]RetryOption{WithTierFunc(testTierFunc), WithTierMultiplier(RetrySlow, 10)},
+			errRateLimited, true, 10,
+		},
+		{
+			"tier overrides retryable func",
+			[]RetryOption{WithIsRetryableFunc(func(error) bool { return false }), WithTierFunc(testTierFunc)},
+			errAlwaysFail, true, 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New(tt.opts...).(*retrier)
+			retryable, multiplier := r.classify(tt.err)
+			assert.Equal(t, tt.wantRetryable, retryable)
+			assert.Equal(t, tt.wantMultiplier, multiplier)
+		})
+	}
+}
+
+func TestRetrier_DoFatalTier(t *testing.T) {
+	calls := 0
+	err := New(
+		WithMaxAttempts(5),
+		WithBackoff(FixedBackoff{Interval: time.Millisecond}),
+		WithTierFunc(testTierFunc),
+	).Do(context.Background(), func(attempt int) error {
+		calls++
+		if attempt < 2 {
+			return errRateLimited
+		}
+		return errCustom
+	})
+
+	assert.True(t, IsUnretryable(err))
+	assert.Equal(t, 3, calls)
+}
+
+func TestTierString(t *testing.T) {
+	assert.Equal(t, "RetrySlow", RetrySlow.String())
+	assert.Equal(t, "Tier(unknown)", Tier(42).String())
+}
+
+func TestScaleDelay(t *testing.T) {
+	assert.Equal(t, time.Second, scaleDelay(time.Second, 1))
+	assert.Equal(t, 2500*time.Millisecond, scaleDelay(time.Second, 2.5))
+}
@@ -0,0 +1,48 @@
+package retry
+
+import (
+	"context"
+	"time"
+)
+
+// WithTimeoutStage returns a decorator bounding any func(ctx) error to d,
+// e.g. as a stage of a resilience pipeline. The decorated function runs f
+// on its own goroutine with a context expiring after d, and returns the
+// context error as soon as that context is done, even if f ignores it. A
+// non-cooperative f then keeps running in the background until it returns,
+// and its result is discarded. A panic in f is propagated to the caller if
+// it is still waiting.
+func WithTimeoutStage(d time.Duration) func(f func(context.Context) error) func(context.Context) error {
+	return func(f func(context.Context) error) func(context.Context) error {
+		return func(ctx context.Context) error {
+			ctx, cancel := context.WithTimeout(ctx, d)
+			defer cancel()
+
+			type result struct {
+				err   error
+				panic any
+			}
+			done := make(chan result, 1)
+			go func() {
+				var res result
+				defer func() {
+					if p := recover(); p != nil {
+						res.panic = p
+					}
+					done <- res
+				}()
+				res.err = f(ctx)
+			}()
+
+			select {
+			case res := <-done:
+				if res.panic != nil {
+					panic(res.panic)
+				}
+				return res.err
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package retry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithTimeoutStage(t *testing.T) {
+	stage := WithTimeoutStage(10 * time.Millisecond)
+
+	t.Run("fast", func(t *testing.T) {
+		err := stage(func(context.Context) error { return errAlwaysFail })(context.Background())
+		assert.ErrorIs(t, err, errAlwaysFail)
+	})
+
+	t.Run("non-cooperative", func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+
+		start := time.Now()
+		err := stage(func(context.Context) error {
+			<-release
+			return nil
+		})(context.Background())
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("parent canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := stage(func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})(ctx)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		assert.PanicsWithValue(t, "boom", func() {
+			_ = stage(func(context.Context) error { panic("boom") })(context.Background())
+		})
+	})
+}
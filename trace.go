@@ -0,0 +1,74 @@
+package retry
+
+import (
+	"errors"
+	"time"
+)
+
+// AttemptRecord describes a failed attempt of a Do call.
+type AttemptRecord struct {
+	// Attempt is the zero-based attempt number.
+	Attempt int
+	// Time is when the attempt started.
+	Time time.Time
+	// Delay is the wait before the next attempt; zero if there was none.
+	Delay time.Duration
+	// Err is the error returned by the attempt.
+	Err error
+}
+
+// WithAttemptTrace makes the errors returned by Do carry a record of every
+// failed attempt, retrievable with AttemptTrace, so a failure can be logged
+// as one line instead of one line per attempt. The error still matches its
+// cause with errors.Is and errors.As.
+func WithAttemptTrace() RetryOption {
+	return func(r *retrier) {
+		r.attemptTrace = true
+	}
+}
+
+// AttemptTrace returns the attempt records carried by err, which are
+// present if the retrier returning err was created with WithAttemptTrace.
+func AttemptTrace(err error) ([]AttemptRecord, bool) {
+	var t *tracedError
+	if errors.As(err, &t) {
+		return t.records, true
+	}
+	return nil, false
+}
+
+// tracedError attaches attempt records to the error returned by Do.
+type tracedError struct {
+	err     error
+	records []AttemptRecord
+}
+
+func (e *tracedError) Error() string { return e.err.Error() }
+func (e *tracedError) Unwrap() error { return e.err }
+
+// attemptTrace collects the records of a Do call.
+type attemptTrace struct {
+	records []AttemptRecord
+}
+
+// failed records a failed attempt.
+func (t *attemptTrace) failed(attempt int, start time.Time, err error) {
+	if t != nil {
+		t.records = append(t.records, AttemptRecord{Attempt: attempt, Time: start, Err: err})
+	}
+}
+
+// sleeping records the delay after the last failed attempt.
+func (t *attemptTrace) sleeping(delay time.Duration) {
+	if t != nil && len(t.records) > 0 {
+		t.records[len(t.records)-1].Delay = delay
+	}
+}
+
+// wrap attaches the records to err.
+func (t *attemptTrace) wrap(err error) error {
+	if t == nil || err == nil {
+		return err
+	}
+	return &tracedError{err: err, records: t.records}
+}
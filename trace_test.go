@@ -0,0 +1,77 @@
+package retry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithAttemptTrace(t *testing.T) {
+	r := New(
+		WithMaxAttempts(3),
+		WithBackoff(FixedBackoff{Interval: time.Millisecond}),
+		WithAttemptTrace(),
+	)
+
+	start := time.Now()
+	err := r.Do(context.Background(), func(attempt int) error {
+		if attempt == 2 {
+			return errCustom
+		}
+		return errAlwaysFail
+	})
+
+	var maxErr *MaxAttemptsError
+	require.ErrorAs(t, err, &maxErr)
+	assert.ErrorIs(t, err, errCustom)
+	assert.Equal(t, CodeExhausted, ErrorCode(err))
+	assert.Equal(t, maxErr.Error(), err.Error())
+
+	records, ok := AttemptTrace(err)
+	require.True(t, ok)
+	require.Len(t, records, 3)
+	for i, rec := range records {
+		assert.Equal(t, i, rec.Attempt)
+		assert.False(t, rec.Time.Before(start))
+	}
+	assert.Equal(t, time.Millisecond, records[0].Delay)
+	assert.Equal(t, time.Millisecond, records[1].Delay)
+	assert.Zero(t, records[2].Delay)
+	assert.Equal(t, []error{errAlwaysFail, errAlwaysFail, errCustom},
+		[]error{records[0].Err, records[1].Err, records[2].Err})
+}
+
+func TestWithAttemptTrace_Unretryable(t *testing.T) {
+	r := New(
+		WithMaxAttempts(3),
+		WithBackoff(FixedBackoff{Interval: time.Millisecond}),
+		WithUnretryableErrors(errCustom),
+		WithAttemptTrace(),
+	)
+
+	err := r.Do(context.Background(), func(attempt int) error {
+		if attempt == 1 {
+			return errCustom
+		}
+		return errAlwaysFail
+	})
+	assert.True(t, IsUnretryable(err))
+
+	records, ok := AttemptTrace(err)
+	require.True(t, ok)
+	assert.Len(t, records, 2)
+}
+
+func TestWithAttemptTrace_Success(t *testing.T) {
+	r := New(WithAttemptTrace())
+	assert.NoError(t, r.Do(context.Background(), func(attempt int) error { return nil }))
+}
+
+func TestAttemptTrace_Disabled(t *testing.T) {
+	r := New(WithMaxAttempts(1))
+	_, ok := AttemptTrace(r.Do(context.Background(), func(attempt int) error { return errAlwaysFail }))
+	assert.False(t, ok)
+}
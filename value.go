@@ -0,0 +1,71 @@
+package retry
+
+import "context"
+
+// ValueFunc is like ContextAttemptFunc but also returns the value produced
+// by a successful attempt.
+type ValueFunc[T any] func(ctx context.Context, attempt int) (T, error)
+
+// valueFunc is the untyped ValueFunc run by retriers.
+type valueFunc func(context.Context, int) (any, error)
+
+// typedValue boxes the values returned by DoValue calls, so a value cached
+// or shared by a call with another type is told apart even when T is an
+// interface type and the value is nil.
+type typedValue[T any] struct{ v T }
+
+// DoValue runs f with r like DoContext and returns the value of the
+// successful attempt, or the zero value along with the error. If r is not
+// a ContextRetrier, attempts receive ctx itself.
+func DoValue[T any](ctx context.Context, r Retrier, f ValueFunc[T]) (T, error) {
+	var zero T
+
+	rr, ok := r.(*retrier)
+	if !ok {
+		var v T
+		err := doContext(ctx, r, func(ctx context.Context, attempt int) error {
+			var err error
+			v, err = f(ctx, attempt)
+			return err
+		})
+		if err != nil {
+			return zero, err
+		}
+		return v, nil
+	}
+
+	v, err := rr.value(ctx, func(ctx context.Context, attempt int) (any, error) {
+		v, err := f(ctx, attempt)
+		return typedValue[T]{v}, err
+	}, func(v any) bool {
+		_, ok := v.(typedValue[T])
+		return ok
+	})
+	if err != nil {
+		return zero, err
+	}
+	return v.(typedValue[T]).v, nil
+}
+
+// value runs f, or returns the cached value if WithResultCache is set and
+// a fresh one of the right type, as reported by fits, is available. A
+// value of another type shared by a deduplicated call is not used either:
+// f is run on its own instead.
+func (r retrier) value(ctx context.Context, f valueFunc, fits func(any) bool) (any, error) {
+	if v, ok := r.cache.get(); ok && fits(v) {
+		return v, nil
+	}
+	v, err := r.run(ctx, f)
+	if err == nil && !fits(v) {
+		v, err = r.exec(ctx, f)
+	}
+	if err == nil {
+		r.cache.set(v)
+	}
+	return v, err
+}
+
+// doContext runs f with r, through DoContext if r is a ContextRetrier.
+func doContext(ctx context.Context, r Retrier, f ContextAttemptFunc) error {
+	return AsContextRetrier(r).DoContext(ctx, f)
+}
@@ -0,0 +1,88 @@
+package retry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// doOnlyRetrier is a Retrier that is not a ContextRetrier.
+type doOnlyRetrier struct{}
+
+func (doOnlyRetrier) Do(_ context.Context, f AttemptFunc) error { return f(0) }
+
+func TestDoValue(t *testing.T) {
+	tests := []struct {
+		name string
+		r    Retrier
+	}{
+		{name: "retrier", r: New(WithBackoff(FixedBackoff{}))},
+		{name: "no retry", r: NoRetry()},
+		{name: "without DoContext", r: doOnlyRetrier{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := DoValue(context.Background(), tt.r, func(context.Context, int) (string, error) {
+				return "token", nil
+			})
+			require.NoError(t, err)
+			assert.Equal(t, "token", v)
+
+			v, err = DoValue(context.Background(), tt.r, func(context.Context, int) (string, error) {
+				return "partial", errAlwaysFail
+			})
+			assert.ErrorIs(t, err, errAlwaysFail)
+			assert.Empty(t, v)
+		})
+	}
+}
+
+func TestDoValue_Retries(t *testing.T) {
+	r := New(WithMaxAttempts(3), WithBackoff(FixedBackoff{}))
+	v, err := DoValue(context.Background(), r, func(_ context.Context, attempt int) (int, error) {
+		if attempt < 2 {
+			return 0, errAlwaysFail
+		}
+		return attempt, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, v)
+}
+
+func TestDoValue_Dedup(t *testing.T) {
+	r := New(WithDedupKeyFunc(func(context.Context) string { return "k" }))
+	g := r.(*retrier).dedup
+
+	release := make(chan struct{})
+	done := make(chan int)
+	go func() {
+		v, _ := DoValue(context.Background(), r, func(context.Context, int) (int, error) {
+			<-release
+			return 42, nil
+		})
+		done <- v
+	}()
+	assert.Eventually(t, func() bool {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		return g.calls["k"] != nil
+	}, time.Second, time.Millisecond)
+
+	joined := make(chan struct{}, 1)
+	go func() {
+		ctx := &joiningContext{Context: context.Background(), joined: joined}
+		v, _ := DoValue(ctx, r, func(context.Context, int) (int, error) {
+			return 0, nil
+		})
+		done <- v
+	}()
+	<-joined
+	close(release)
+
+	assert.Equal(t, 42, <-done)
+	assert.Equal(t, 42, <-done, "joining calls receive the shared value")
+}
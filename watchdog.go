@@ -0,0 +1,34 @@
+package retry
+
+import "time"
+
+// LeakFunc is called for an attempt still running after its attempt
+// timeout expired, with how long it has been overdue.
+type LeakFunc func(attempt int, overdue time.Duration)
+
+// WithLeakWatchdog calls fn for every attempt still running grace after
+// the deadline set by WithAttemptTimeout, surfacing callees that ignore
+// their context: they hold up the call past its deadline, and leak
+// goroutines wherever their caller stops waiting for them. fn is called at
+// most once per attempt, on a separate goroutine; typically it logs or
+// increments a metric. Without an attempt timeout it has no effect.
+func WithLeakWatchdog(grace time.Duration, fn LeakFunc) RetryOption {
+	return func(r *retrier) {
+		r.leakGrace = grace
+		r.leakFunc = fn
+	}
+}
+
+// watchLeak starts the leak watchdog for an attempt and returns a function
+// stopping it once the attempt returns.
+func (r retrier) watchLeak(attempt int) (stop func() bool) {
+	if r.leakFunc == nil {
+		return func() bool { return false }
+	}
+
+	deadline := time.Now().Add(r.attemptTimeout)
+	t := time.AfterFunc(r.attemptTimeout+r.leakGrace, func() {
+		r.leakFunc(attempt, time.Since(deadline))
+	})
+	return t.Stop
+}
@@ -0,0 +1,54 @@
+package retry
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithLeakWatchdog(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		leaks = map[int]time.Duration{}
+	)
+	r := New(
+		WithMaxAttempts(2),
+		WithBackoff(FixedBackoff{}),
+		WithAttemptTimeout(5*time.Millisecond),
+		WithLeakWatchdog(5*time.Millisecond, func(attempt int, overdue time.Duration) {
+			mu.Lock()
+			leaks[attempt] = overdue
+			mu.Unlock()
+		}),
+	)
+
+	err := r.DoContext(context.Background(), func(ctx context.Context, attempt int) error {
+		if attempt == 0 {
+			time.Sleep(30 * time.Millisecond) // ignores ctx
+			return ctx.Err()
+		}
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	require.ErrorIs(t, err, ErrAttemptTimeout)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Contains(t, leaks, 0)
+	assert.GreaterOrEqual(t, leaks[0], 5*time.Millisecond)
+	assert.NotContains(t, leaks, 1, "a cooperative attempt is not reported")
+}
+
+func TestWithLeakWatchdog_NoTimeout(t *testing.T) {
+	called := false
+	r := New(WithLeakWatchdog(0, func(int, time.Duration) { called = true }))
+	_ = r.Do(context.Background(), func(attempt int) error {
+		time.Sleep(time.Millisecond)
+		return nil
+	})
+	assert.False(t, called)
+}